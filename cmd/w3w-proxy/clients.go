@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+)
+
+// client is an application allowed to use the proxy, identified either by
+// the origin of the web pages calling it or by a token.
+type client struct {
+	// Name identifies the client in logs.
+	Name string `json:"name"`
+	// Origins are the origins, such as https://app.example.com, of the web
+	// pages allowed to call the proxy. Browsers send the origin of pages
+	// making cross-origin requests, but other clients can set any origin, so
+	// origins only stop other websites from using the proxy.
+	Origins []string `json:"origins,omitempty"`
+	// Tokens are sent by the client in place of the what3words API key,
+	// either in the X-Api-Key header or in the key query parameter, so that
+	// existing SDKs can use the proxy unchanged.
+	Tokens []string `json:"tokens,omitempty"`
+	// Endpoints the client may call, such as autosuggest. All endpoints
+	// when empty.
+	Endpoints []string `json:"endpoints,omitempty"`
+}
+
+// allows reports whether the client may call the endpoint.
+func (c *client) allows(endpoint string) bool {
+	if len(c.Endpoints) == 0 {
+		return true
+	}
+	for _, allowed := range c.Endpoints {
+		if allowed == endpoint {
+			return true
+		}
+	}
+	return false
+}
+
+// allowlist identifies the client making a request.
+type allowlist struct {
+	byOrigin map[string]*client
+	byToken  map[string]*client
+	// open allows requests from anyone, as an anonymous client.
+	open bool
+}
+
+// loadAllowlist reads the clients from the JSON file at path, holding an
+// array of clients.
+func loadAllowlist(path string) (*allowlist, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var clients []*client
+	if err := json.Unmarshal(data, &clients); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return newAllowlist(clients)
+}
+
+func newAllowlist(clients []*client) (*allowlist, error) {
+	al := &allowlist{
+		byOrigin: make(map[string]*client),
+		byToken:  make(map[string]*client),
+	}
+	for _, c := range clients {
+		if len(c.Origins) == 0 && len(c.Tokens) == 0 {
+			return nil, fmt.Errorf("client %q has neither origins nor tokens", c.Name)
+		}
+		for _, origin := range c.Origins {
+			origin = strings.TrimSuffix(origin, "/")
+			if _, ok := al.byOrigin[origin]; ok {
+				return nil, fmt.Errorf("origin %q is listed more than once", origin)
+			}
+			al.byOrigin[origin] = c
+		}
+		for _, token := range c.Tokens {
+			if _, ok := al.byToken[token]; ok {
+				return nil, fmt.Errorf("a token of client %q is listed more than once", c.Name)
+			}
+			al.byToken[token] = c
+		}
+	}
+	return al, nil
+}
+
+var anonymous = &client{Name: "anonymous"}
+
+// allowsOrigin reports whether web pages of the origin may read the
+// responses of the proxy, the origin being allowlisted or the proxy open.
+func (al *allowlist) allowsOrigin(origin string) bool {
+	_, ok := al.byOrigin[origin]
+	return ok || al.open
+}
+
+// identify returns the client making the request. A token takes precedence
+// over the origin, as browsers always send the origin of cross-origin
+// requests. Unknown clients get the error the API returns for an invalid
+// key or referrer, so that SDKs report it as an authentication error.
+func (al *allowlist) identify(r *http.Request) (*client, *v3.ErrorResponse) {
+	token := r.Header.Get("X-Api-Key")
+	if token == "" {
+		token = r.URL.Query().Get("key")
+	}
+	origin := r.Header.Get("Origin")
+	if c, ok := al.byToken[token]; ok && token != "" {
+		return c, nil
+	}
+	if c, ok := al.byOrigin[origin]; ok && origin != "" {
+		return c, nil
+	}
+	switch {
+	case al.open:
+		return anonymous, nil
+	case token != "":
+		return nil, &v3.ErrorResponse{Code: v3.ErrorCodeInvalidKey, Message: "Authentication failed; invalid API key"}
+	case origin != "":
+		return nil, &v3.ErrorResponse{Code: v3.ErrorCodeInvalidReferrer, Message: "Origin " + origin + " is not allowed"}
+	}
+	return nil, &v3.ErrorResponse{Code: v3.ErrorCodeMissingKey, Message: "Authentication failed; missing API key"}
+}
@@ -0,0 +1,140 @@
+// Command w3w-proxy is an HTTP server exposing the /v3 routes of the
+// what3words API, so that browser and mobile applications can use the API
+// without ever seeing its key.
+//
+// The key is added to the requests sent to the API by the proxy, which is
+// built on the v3 package and applies its caching, rate limiting and logging.
+// Error responses of the API are passed through unchanged, so that existing
+// SDKs can be pointed at the proxy with their custom base URL option.
+//
+// Usage:
+//
+//	W3W_API_KEY=<key> w3w-proxy --clients clients.json --listen :8080
+//
+// Only the clients listed in the clients file may use the proxy. The file
+// holds a JSON array of clients, each identified by the origins of the web
+// pages calling the proxy or by tokens sent in place of the API key, and
+// optionally restricted to some endpoints:
+//
+//	[
+//	  {"name": "web", "origins": ["https://app.example.com"]},
+//	  {"name": "ios", "tokens": ["<token>"], "endpoints": ["autosuggest", "convert-to-coordinates"]}
+//	]
+//
+// The flags are:
+//
+//	--listen      address to listen on, defaults to :8080
+//	--key         API key, defaults to the W3W_API_KEY environment variable
+//	--base-url    base URL of the API, for example an enterprise server
+//	--clients     JSON file listing the clients allowed to use the proxy
+//	--allow-any   allow requests from anyone when no clients file is given
+//	--cache-size  number of responses cached in memory, 0 to disable caching
+//	--cache-ttl   time responses are cached for
+//	--rate        requests per second sent to the API, 0 for no limit
+//	--burst       requests sent at once to the API when rate limited
+//	--timeout     timeout of the calls to the API made for each request
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/cache"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "w3w-proxy: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("w3w-proxy", flag.ContinueOnError)
+	listen := fs.String("listen", ":8080", "address to listen on")
+	key := fs.String("key", "", "API key, defaults to the W3W_API_KEY environment variable")
+	baseURL := fs.String("base-url", "", "base URL of the API, for example an enterprise server")
+	clientsFile := fs.String("clients", "", "JSON file listing the clients allowed to use the proxy")
+	allowAny := fs.Bool("allow-any", false, "allow requests from anyone when no clients file is given")
+	cacheSize := fs.Int("cache-size", 10000, "number of responses cached in memory, 0 to disable caching")
+	cacheTTL := fs.Duration("cache-ttl", 24*time.Hour, "time responses are cached for")
+	rate := fs.Float64("rate", 0, "requests per second sent to the API, 0 for no limit")
+	burst := fs.Int("burst", 10, "requests sent at once to the API when rate limited")
+	timeout := fs.Duration("timeout", 10*time.Second, "timeout of the calls to the API made for each request")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *key == "" {
+		*key = os.Getenv("W3W_API_KEY")
+	}
+	if *key == "" {
+		return errors.New("missing API key, set --key or W3W_API_KEY")
+	}
+
+	var clients *allowlist
+	var err error
+	switch {
+	case *clientsFile != "":
+		if clients, err = loadAllowlist(*clientsFile); err != nil {
+			return err
+		}
+	case *allowAny:
+		clients, _ = newAllowlist(nil)
+		clients.open = true
+	default:
+		return errors.New("missing --clients, or --allow-any to allow requests from anyone")
+	}
+
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+	opts := []v3.APIOption{
+		v3.WithLogger(logger),
+		// The API reports invalid options with the error codes SDKs expect.
+		v3.WithoutValidation(),
+		v3.WithRequestDeduplication(),
+	}
+	if *baseURL != "" {
+		opts = append(opts, v3.WithCustomBaseURL(strings.TrimSuffix(*baseURL, "/")))
+	}
+	if *cacheSize > 0 {
+		opts = append(opts, v3.WithCache(cache.NewLRU(*cacheSize), *cacheTTL))
+	}
+	if *rate > 0 {
+		opts = append(opts, v3.WithRateLimit(*rate, *burst))
+	}
+	p := newProxy(v3.NewAPI(*key, opts...), clients, logger)
+	p.timeout = *timeout
+
+	srv := &http.Server{
+		Addr:              *listen,
+		Handler:           p,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	errc := make(chan error, 1)
+	go func() {
+		logger.Info("listening", "address", *listen)
+		errc <- srv.ListenAndServe()
+	}()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
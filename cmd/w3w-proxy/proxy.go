@@ -0,0 +1,329 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+// proxy serves the /v3 routes of the what3words API using a v3.API
+// holding the API key, so that clients never see it.
+type proxy struct {
+	api     v3.API
+	clients *allowlist
+	log     *slog.Logger
+	// timeout bounds the calls made to the API for each request.
+	timeout time.Duration
+	routes  map[string]func(context.Context, url.Values) (any, error)
+}
+
+func newProxy(api v3.API, clients *allowlist, log *slog.Logger) *proxy {
+	p := &proxy{api: api, clients: clients, log: log}
+	p.routes = map[string]func(context.Context, url.Values) (any, error){
+		v3.EndpointConvertTo3wa:               p.convertTo3wa,
+		v3.EndpointConvertToCoordinates:       p.convertToCoordinates,
+		v3.EndpointAutoSuggest:                p.autoSuggest,
+		v3.EndpointAutoSuggestWithCoordinates: p.autoSuggestWithCoordinates,
+		v3.EndpointAutoSuggestSelection:       p.autoSuggestSelection,
+		v3.EndpointGridSection:                p.gridSection,
+		v3.EndpointAvailableLanguages:         p.availableLanguages,
+	}
+	return p
+}
+
+func (p *proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		w.Header().Add("Vary", "Origin")
+		if p.clients.allowsOrigin(origin) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		}
+	}
+	if r.Method == http.MethodOptions {
+		// Preflight requests carry no token, so they are answered for any
+		// origin, browsers only send the request that follows for
+		// allowlisted origins, where it is checked as usual.
+		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", r.Header.Get("Access-Control-Request-Headers"))
+		w.Header().Set("Access-Control-Max-Age", "86400")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	endpoint, ok := strings.CutPrefix(r.URL.Path, "/v3/")
+	route := p.routes[endpoint]
+	if !ok || route == nil {
+		writeError(w, http.StatusNotFound, &v3.ErrorResponse{Code: "NotFound", Message: "Unknown endpoint " + r.URL.Path})
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET, OPTIONS")
+		writeError(w, http.StatusMethodNotAllowed, &v3.ErrorResponse{Code: "MethodNotAllowed", Message: "Only GET requests are supported"})
+		return
+	}
+	c, errResp := p.clients.identify(r)
+	if errResp != nil {
+		p.log.Warn("rejected request", "endpoint", endpoint, "origin", r.Header.Get("Origin"), "code", errResp.Code)
+		writeError(w, http.StatusUnauthorized, errResp)
+		return
+	}
+	if !c.allows(endpoint) {
+		p.log.Warn("rejected request", "client", c.Name, "endpoint", endpoint)
+		writeError(w, http.StatusUnauthorized, &v3.ErrorResponse{
+			Code:    v3.ErrorCodeInvalidKey,
+			Message: "Authentication failed; this key is not allowed to call " + endpoint,
+		})
+		return
+	}
+
+	ctx := r.Context()
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+	resp, err := route(ctx, r.URL.Query())
+	if err != nil {
+		p.writeAPIError(w, c, endpoint, err)
+		return
+	}
+	if resp == nil {
+		// The endpoint only reports an event, the API answers with an empty body.
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.Encode(resp)
+}
+
+// writeAPIError writes the error of a call to the API. Error responses of
+// the API are passed through unchanged, with their status code. Bodies
+// truncated by the HTTPError are written again from the decoded error.
+func (p *proxy) writeAPIError(w http.ResponseWriter, c *client, endpoint string, err error) {
+	var httpErr *v3.HTTPError
+	var errResp *v3.ErrorResponse
+	switch {
+	case errors.As(err, &httpErr) && errors.As(err, &errResp):
+		if retryAfter := httpErr.Header.Get("Retry-After"); retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		if !json.Valid(httpErr.Body) {
+			writeError(w, httpErr.StatusCode, errResp)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(httpErr.StatusCode)
+		w.Write(httpErr.Body)
+	case errors.As(err, &errResp):
+		// Raised by the proxy while reading the query parameters.
+		writeError(w, http.StatusBadRequest, errResp)
+	case errors.Is(err, context.Canceled):
+		// The client went away, there is no one to answer.
+	case errors.Is(err, context.DeadlineExceeded):
+		writeError(w, http.StatusGatewayTimeout, &v3.ErrorResponse{Code: v3.ErrorCodeInternalServerError, Message: "The what3words API did not respond in time"})
+	case errors.Is(err, v3.ErrCircuitOpen):
+		writeError(w, http.StatusServiceUnavailable, &v3.ErrorResponse{Code: v3.ErrorCodeInternalServerError, Message: "The what3words API is unavailable"})
+	default:
+		p.log.Error("request failed", "client", c.Name, "endpoint", endpoint, "error", err)
+		writeError(w, http.StatusBadGateway, &v3.ErrorResponse{Code: v3.ErrorCodeInternalServerError, Message: "The what3words API could not be reached"})
+	}
+}
+
+// writeError writes an error in the format of the API.
+func writeError(w http.ResponseWriter, status int, errResp *v3.ErrorResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(struct {
+		Error *v3.ErrorResponse `json:"error"`
+	}{errResp})
+}
+
+func (p *proxy) convertTo3wa(ctx context.Context, q url.Values) (any, error) {
+	if q.Get("coordinates") == "" {
+		return nil, &v3.ErrorResponse{Code: v3.ErrorCodeMissingCoordinates, Message: "coordinates must be specified"}
+	}
+	coordinates, err := parseCoordinates(q.Get("coordinates"))
+	if err != nil {
+		return nil, &v3.ErrorResponse{Code: v3.ErrorCodeBadCoordinates, Message: "coordinates must be two comma separated lat,lng coordinates"}
+	}
+	opts := convertOpts(q)
+	if isGeoJSON(q) {
+		return p.api.ConvertTo3waGeoJson(ctx, coordinates, opts)
+	}
+	return p.api.ConvertTo3wa(ctx, coordinates, opts)
+}
+
+func (p *proxy) convertToCoordinates(ctx context.Context, q url.Values) (any, error) {
+	words := q.Get("words")
+	if words == "" {
+		return nil, &v3.ErrorResponse{Code: v3.ErrorCodeMissingWords, Message: "words must be specified"}
+	}
+	opts := convertOpts(q)
+	if isGeoJSON(q) {
+		return p.api.ConvertToCoordinatesGeoJson(ctx, words, opts)
+	}
+	return p.api.ConvertToCoordinates(ctx, words, opts)
+}
+
+func (p *proxy) autoSuggest(ctx context.Context, q url.Values) (any, error) {
+	opts, err := autoSuggestOpts(q)
+	if err != nil {
+		return nil, err
+	}
+	return p.api.AutoSuggest(ctx, q.Get("input"), opts)
+}
+
+func (p *proxy) autoSuggestWithCoordinates(ctx context.Context, q url.Values) (any, error) {
+	opts, err := autoSuggestOpts(q)
+	if err != nil {
+		return nil, err
+	}
+	return p.api.AutoSuggestWithCoordinates(ctx, q.Get("input"), opts)
+}
+
+func (p *proxy) autoSuggestSelection(ctx context.Context, q url.Values) (any, error) {
+	opts, err := autoSuggestOpts(q)
+	if err != nil {
+		return nil, err
+	}
+	selection := v3.AutoSuggestSuggestion{Words: q.Get("selection")}
+	if s := q.Get("rank"); s != "" {
+		if selection.Rank, err = strconv.Atoi(s); err != nil {
+			return nil, &v3.ErrorResponse{Code: v3.ErrorCodeBadInput, Message: "rank must be a positive integer"}
+		}
+	}
+	return nil, p.api.AutoSuggestSelection(ctx, q.Get("raw-input"), selection, opts)
+}
+
+func (p *proxy) gridSection(ctx context.Context, q url.Values) (any, error) {
+	if q.Get("bounding-box") == "" {
+		return nil, &v3.ErrorResponse{Code: v3.ErrorCodeMissingBoundingBox, Message: "bounding-box must be specified"}
+	}
+	values, err := parseFloats(q.Get("bounding-box"), 4)
+	if err != nil {
+		return nil, &v3.ErrorResponse{Code: v3.ErrorCodeBadBoundingBox, Message: "bounding-box must be four comma separated coordinates"}
+	}
+	box := v3.BoundingBox{
+		SouthWest: core.Coordinates{Lat: values[0], Lng: values[1]},
+		NorthEast: core.Coordinates{Lat: values[2], Lng: values[3]},
+	}
+	if isGeoJSON(q) {
+		return p.api.GridSectionGeoJson(ctx, box)
+	}
+	return p.api.GridSection(ctx, box)
+}
+
+func (p *proxy) availableLanguages(ctx context.Context, _ url.Values) (any, error) {
+	return p.api.AvailableLanguages(ctx)
+}
+
+func isGeoJSON(q url.Values) bool {
+	return q.Get("format") == "geojson"
+}
+
+func convertOpts(q url.Values) *v3.ConvertAPIOpts {
+	return &v3.ConvertAPIOpts{Language: q.Get("language"), Locale: q.Get("locale")}
+}
+
+// autoSuggestOpts reads the options of the autosuggest endpoints. Values
+// which can not be parsed are reported with the error code of the API.
+func autoSuggestOpts(q url.Values) (*v3.AutoSuggestOpts, error) {
+	opts := &v3.AutoSuggestOpts{
+		Language:  q.Get("language"),
+		Locale:    q.Get("locale"),
+		InputType: v3.InputType(q.Get("input-type")),
+	}
+	if s := q.Get("focus"); s != "" {
+		focus, err := parseCoordinates(s)
+		if err != nil {
+			return nil, &v3.ErrorResponse{Code: v3.ErrorCodeBadFocus, Message: "focus must be two comma separated lat,lng coordinates"}
+		}
+		opts.Focus = &focus
+	}
+	if s := q.Get("clip-to-country"); s != "" {
+		opts.ClipToCountry = strings.Split(s, ",")
+	}
+	if s := q.Get("clip-to-bounding-box"); s != "" {
+		values, err := parseFloats(s, 4)
+		if err != nil {
+			return nil, &v3.ErrorResponse{Code: v3.ErrorCodeBadClipToBoundingBox, Message: "clip-to-bounding-box must be four comma separated coordinates"}
+		}
+		opts.ClipToBoundingBox = &v3.BoundingBox{
+			SouthWest: core.Coordinates{Lat: values[0], Lng: values[1]},
+			NorthEast: core.Coordinates{Lat: values[2], Lng: values[3]},
+		}
+	}
+	if s := q.Get("clip-to-circle"); s != "" {
+		values, err := parseFloats(s, 3)
+		if err != nil {
+			return nil, &v3.ErrorResponse{Code: v3.ErrorCodeBadClipToCircle, Message: "clip-to-circle must be a lat,lng centre followed by a radius in kilometres"}
+		}
+		opts.ClipToCircle = &v3.Circle{Center: core.Coordinates{Lat: values[0], Lng: values[1]}, RadiusKm: values[2]}
+	}
+	if s := q.Get("clip-to-polygon"); s != "" {
+		values, err := parseFloats(s, -1)
+		if err != nil || len(values)%2 != 0 {
+			return nil, &v3.ErrorResponse{Code: v3.ErrorCodeBadClipToPolygon, Message: "clip-to-polygon must be a list of comma separated lat,lng coordinates"}
+		}
+		for i := 0; i < len(values); i += 2 {
+			opts.ClipToPolygon = append(opts.ClipToPolygon, core.Coordinates{Lat: values[i], Lng: values[i+1]})
+		}
+	}
+	if s := q.Get("prefer-land"); s != "" {
+		preferLand, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, &v3.ErrorResponse{Code: v3.ErrorCodeBadPreferLand, Message: "prefer-land must be true or false"}
+		}
+		opts.PreferLand = core.Bool(preferLand)
+	}
+	if s := q.Get("n-results"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, &v3.ErrorResponse{Code: v3.ErrorCodeBadNResults, Message: "n-results must be a positive integer"}
+		}
+		opts.NResults = core.Int(n)
+	}
+	if s := q.Get("n-focus-results"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, &v3.ErrorResponse{Code: v3.ErrorCodeBadNFocusResults, Message: "n-focus-results must be a positive integer"}
+		}
+		opts.NFocusResult = core.Int(n)
+	}
+	return opts, nil
+}
+
+// parseFloats parses comma separated numbers, expecting n of them unless n is -1.
+func parseFloats(s string, n int) ([]float64, error) {
+	parts := strings.Split(s, ",")
+	if n >= 0 && len(parts) != n {
+		return nil, errors.New("unexpected number of values")
+	}
+	values := make([]float64, len(parts))
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func parseCoordinates(s string) (core.Coordinates, error) {
+	values, err := parseFloats(s, 2)
+	if err != nil {
+		return core.Coordinates{}, err
+	}
+	return core.Coordinates{Lat: values[0], Lng: values[1]}, nil
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/cache"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+	"github.com/what3words/w3w-go-wrapper/pkg/w3wtest"
+)
+
+const badWordsBody = `{"error":{"code":"BadWords","message":"Invalid or non-existent 3 word address"}}`
+
+// upstream fakes the what3words API, expecting the key of the proxy.
+func upstream(t *testing.T, hits *atomic.Int32) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.Header.Get("X-Api-Key") != "server-key" || r.URL.Query().Has("key") {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"code":"InvalidKey","message":"Authentication failed; invalid API key"}}`))
+			return
+		}
+		if r.URL.Query().Get("words") == "long.error.message" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":"BadWords","message":"` + strings.Repeat("x", 2000) + `"}}`))
+			return
+		}
+		if r.URL.Query().Get("words") == "not.a.word" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(badWordsBody))
+			return
+		}
+		w.Write([]byte(`{"country":"GB","coordinates":{"lat":51.520847,"lng":-0.195521},"words":"filled.count.soap","language":"en"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func proxyServer(t *testing.T, hits *atomic.Int32, opts ...v3.APIOption) *httptest.Server {
+	clients, err := newAllowlist([]*client{
+		{Name: "web", Origins: []string{"https://app.example.com"}},
+		{Name: "ios", Tokens: []string{"ios-token"}},
+		{Name: "kiosk", Tokens: []string{"kiosk-token"}, Endpoints: []string{v3.EndpointAutoSuggest}},
+	})
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	opts = append([]v3.APIOption{v3.WithCustomBaseURL(upstream(t, hits).URL), v3.WithoutValidation()}, opts...)
+	p := newProxy(v3.NewAPI("server-key", opts...), clients, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	p.timeout = 5 * time.Second
+	srv := httptest.NewServer(p)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func get(t *testing.T, url string, header map[string]string) (*http.Response, string) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func TestProxyWithExistingSDK(t *testing.T) {
+	var hits atomic.Int32
+	srv := proxyServer(t, &hits)
+	api := v3.NewAPI("ios-token", v3.WithCustomBaseURL(srv.URL))
+
+	resp, err := api.ConvertToCoordinates(context.Background(), "filled.count.soap", nil)
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if resp.Words != "filled.count.soap" {
+		t.Fatalf("ERROR: Expected filled.count.soap, got %q", resp.Words)
+	}
+
+	_, err = api.ConvertToCoordinates(context.Background(), "not.a.word", nil)
+	var httpErr *v3.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadRequest || string(httpErr.Body) != badWordsBody {
+		t.Fatalf("ERROR: Expected the error response of the API unchanged, got %v", err)
+	}
+	if !errors.Is(err, v3.ErrBadWords) {
+		t.Fatalf("ERROR: Expected ErrBadWords, got %v", err)
+	}
+	_, err = api.ConvertToCoordinates(context.Background(), "long.error.message", nil)
+	var errResp *v3.ErrorResponse
+	if !errors.As(err, &errResp) || len(errResp.Message) != 2000 {
+		t.Fatalf("ERROR: Expected long error responses of the API in full, got %v", err)
+	}
+
+	selection := v3.AutoSuggestSuggestion{Words: "filled.count.soap", Rank: 1}
+	if err := api.AutoSuggestSelection(context.Background(), "filled.count.so", selection, nil); err != nil {
+		t.Fatalf("ERROR: Expected the selection to be reported, got %v", err)
+	}
+}
+
+func TestProxyAllowlist(t *testing.T) {
+	var hits atomic.Int32
+	srv := proxyServer(t, &hits)
+	path := srv.URL + "/v3/convert-to-3wa?coordinates=51.520847,-0.195521"
+
+	tests := []struct {
+		name   string
+		url    string
+		header map[string]string
+		status int
+		code   string
+	}{
+		{"origin", path, map[string]string{"Origin": "https://app.example.com"}, http.StatusOK, ""},
+		{"token header", path, map[string]string{"X-Api-Key": "ios-token"}, http.StatusOK, ""},
+		{"token query", path + "&key=ios-token", nil, http.StatusOK, ""},
+		{"unknown origin", path, map[string]string{"Origin": "https://evil.example.com"}, http.StatusUnauthorized, "InvalidReferrer"},
+		{"unknown token", path, map[string]string{"X-Api-Key": "stolen"}, http.StatusUnauthorized, "InvalidKey"},
+		{"anonymous", path, nil, http.StatusUnauthorized, "MissingKey"},
+		{"endpoint not allowed", path, map[string]string{"X-Api-Key": "kiosk-token"}, http.StatusUnauthorized, "InvalidKey"},
+	}
+	for _, test := range tests {
+		resp, body := get(t, test.url, test.header)
+		if resp.StatusCode != test.status {
+			t.Fatalf("ERROR: %s: expected status %d, got %d: %s", test.name, test.status, resp.StatusCode, body)
+		}
+		if test.code != "" && !strings.Contains(body, `"code":"`+test.code+`"`) {
+			t.Fatalf("ERROR: %s: expected error code %s, got %s", test.name, test.code, body)
+		}
+	}
+	resp, _ := get(t, path, map[string]string{"Origin": "https://app.example.com"})
+	if resp.Header.Get("Access-Control-Allow-Origin") != "https://app.example.com" {
+		t.Fatalf("ERROR: Expected CORS headers for an allowed origin, got %v", resp.Header)
+	}
+	resp, _ = get(t, path, map[string]string{"Origin": "https://evil.example.com", "X-Api-Key": "ios-token"})
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("ERROR: Expected no CORS headers for an origin which is not allowlisted, got %v", resp.Header)
+	}
+	if n := hits.Load(); n != 5 {
+		t.Fatalf("ERROR: Expected only allowed requests to reach the API, got %d", n)
+	}
+}
+
+func TestProxyCachesResponses(t *testing.T) {
+	var hits atomic.Int32
+	srv := proxyServer(t, &hits, v3.WithCache(cache.NewLRU(10), time.Minute))
+	for i := 0; i < 3; i++ {
+		resp, body := get(t, srv.URL+"/v3/convert-to-coordinates?words=filled.count.soap", map[string]string{"X-Api-Key": "ios-token"})
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("ERROR: Expected status 200, got %d: %s", resp.StatusCode, body)
+		}
+	}
+	if n := hits.Load(); n != 1 {
+		t.Fatalf("ERROR: Expected 1 request to the API, got %d", n)
+	}
+}
+
+func TestProxyRejectsBadParameters(t *testing.T) {
+	var hits atomic.Int32
+	srv := proxyServer(t, &hits)
+	resp, body := get(t, srv.URL+"/v3/autosuggest?input=filled.count.so&focus=north", map[string]string{"X-Api-Key": "ios-token"})
+	if resp.StatusCode != http.StatusBadRequest || !strings.Contains(body, `"code":"BadFocus"`) {
+		t.Fatalf("ERROR: Expected a BadFocus error, got %d: %s", resp.StatusCode, body)
+	}
+	resp, _ = get(t, srv.URL+"/v3/unknown", map[string]string{"X-Api-Key": "ios-token"})
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("ERROR: Expected status 404, got %d", resp.StatusCode)
+	}
+	if n := hits.Load(); n != 0 {
+		t.Fatalf("ERROR: Expected no request to the API, got %d", n)
+	}
+}
+
+func TestProxyForwardsResponsesUnchanged(t *testing.T) {
+	fake := w3wtest.NewServer()
+	defer fake.Close()
+	clients, err := newAllowlist([]*client{{Name: "ios", Tokens: []string{"ios-token"}}})
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	p := newProxy(v3.NewAPI("server-key", v3.WithCustomBaseURL(fake.URL)), clients, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	p.timeout = 5 * time.Second
+	srv := httptest.NewServer(p)
+	defer srv.Close()
+
+	words := w3wtest.Words(core.Coordinates{Lat: 51.520847, Lng: -0.195521})
+	input := url.QueryEscape(words[:len(words)-2])
+	tests := []struct {
+		query    string
+		contains []string
+		excludes []string
+	}{
+		{"/v3/convert-to-3wa?coordinates=51.520847,-0.195521&format=geojson", []string{`"bbox"`, `"geometry"`}, []string{`"Bbox"`, `"Geometry"`}},
+		{"/v3/convert-to-coordinates?words=" + words + "&language=de&locale=de_at", []string{`"locale":"de_at"`}, nil},
+		{"/v3/autosuggest?input=" + input, []string{`"suggestions"`}, []string{`"distanceToFocusKm"`, `"locale"`}},
+		{"/v3/autosuggest?input=" + input + "&language=de&locale=de_at", []string{`"locale":"de_at"`}, []string{`"distanceToFocusKm"`}},
+	}
+	for _, test := range tests {
+		resp, body := get(t, srv.URL+test.query, map[string]string{"X-Api-Key": "ios-token"})
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("ERROR: %s: expected status 200, got %d: %s", test.query, resp.StatusCode, body)
+		}
+		_, upstreamBody := get(t, fake.URL+test.query, map[string]string{"X-Api-Key": "server-key"})
+		var expected, got any
+		if err := json.Unmarshal([]byte(upstreamBody), &expected); err != nil {
+			t.Fatalf("ERROR: Got error %v", err)
+		}
+		if err := json.Unmarshal([]byte(body), &got); err != nil {
+			t.Fatalf("ERROR: Got error %v", err)
+		}
+		if !reflect.DeepEqual(expected, got) {
+			t.Fatalf("ERROR: %s: expected the response of the API %s, got %s", test.query, upstreamBody, body)
+		}
+		for _, key := range test.contains {
+			if !strings.Contains(body, key) {
+				t.Fatalf("ERROR: %s: expected %s in %s", test.query, key, body)
+			}
+		}
+		for _, key := range test.excludes {
+			if strings.Contains(body, key) {
+				t.Fatalf("ERROR: %s: expected no %s in %s", test.query, key, body)
+			}
+		}
+	}
+}
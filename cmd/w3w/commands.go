@@ -0,0 +1,472 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	w3w "github.com/what3words/w3w-go-wrapper"
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+func init() {
+	register(command{
+		name:  "convert-to-3wa",
+		usage: "<lat,lng>",
+		short: "Convert coordinates to a three word address",
+		flags: convertFlags,
+		run:   runConvertTo3wa,
+	})
+	register(command{
+		name:  "convert-to-coordinates",
+		usage: "<words>",
+		short: "Convert a three word address to coordinates",
+		flags: convertFlags,
+		run:   runConvertToCoordinates,
+	})
+	register(command{
+		name:  "autosuggest",
+		usage: "<input>",
+		short: "Suggest three word addresses for a full or partial input",
+		flags: autosuggestFlags,
+		run:   runAutosuggest,
+	})
+	register(command{
+		name:  "grid-section",
+		usage: "<south_lat,west_lng,north_lat,east_lng>",
+		short: "Print the grid lines within a bounding box",
+		run:   runGridSection,
+	})
+	register(command{
+		name:  "languages",
+		short: "List the available three word address languages",
+		run:   runLanguages,
+	})
+	register(command{
+		name:  "find",
+		usage: "< text",
+		short: "Find possible three word addresses in the text read from stdin",
+		run:   runFind,
+	})
+	register(command{
+		name:  "validate",
+		usage: "[words...]",
+		short: "Check three word addresses exist, reading them from stdin when none are given",
+		run:   runValidate,
+	})
+}
+
+func convertFlags(fs *flag.FlagSet) any {
+	opts := &v3.ConvertAPIOpts{}
+	fs.StringVar(&opts.Language, "language", "", "language of the three word address, as an ISO 639-1 2 letter code")
+	fs.StringVar(&opts.Locale, "locale", "", "locale of the three word address, for a variant of the language")
+	return opts
+}
+
+func runConvertTo3wa(ctx context.Context, c *common, opts any, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	coordinates, err := parseCoordinates(args[0])
+	if err != nil {
+		return err
+	}
+	svc, err := c.service()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	if c.format == formatGeoJSON {
+		resp, err := svc.V3().ConvertTo3waGeoJson(ctx, coordinates, opts.(*v3.ConvertAPIOpts))
+		if err != nil {
+			return err
+		}
+		return writeJSON(c.stdout, resp)
+	}
+	resp, err := svc.V3().ConvertTo3wa(ctx, coordinates, opts.(*v3.ConvertAPIOpts))
+	if err != nil {
+		return err
+	}
+	return writeConvert(c, resp)
+}
+
+func runConvertToCoordinates(ctx context.Context, c *common, opts any, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	words := strings.TrimPrefix(args[0], "///")
+	svc, err := c.service()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	if c.format == formatGeoJSON {
+		resp, err := svc.V3().ConvertToCoordinatesGeoJson(ctx, words, opts.(*v3.ConvertAPIOpts))
+		if err != nil {
+			return err
+		}
+		return writeJSON(c.stdout, resp)
+	}
+	resp, err := svc.V3().ConvertToCoordinates(ctx, words, opts.(*v3.ConvertAPIOpts))
+	if err != nil {
+		return err
+	}
+	return writeConvert(c, resp)
+}
+
+func writeConvert(c *common, resp *v3.ConvertAPIJsonResponse) error {
+	if c.format == formatJSON {
+		return writeJSON(c.stdout, resp)
+	}
+	return writeTable(c.stdout,
+		[]string{"WORDS", "COORDINATES", "COUNTRY", "NEAREST PLACE", "LANGUAGE", "MAP"},
+		[][]string{{resp.Words, formatCoordinates(resp.Coordinates), resp.Country, resp.NearestPlace, resp.Language, resp.MapUrl}},
+	)
+}
+
+// autosuggestOptions holds the flags of the autosuggest command.
+type autosuggestOptions struct {
+	opts            v3.AutoSuggestOpts
+	withCoordinates bool
+}
+
+func autosuggestFlags(fs *flag.FlagSet) any {
+	o := &autosuggestOptions{}
+	fs.StringVar(&o.opts.Language, "language", "", "fallback language, as an ISO 639-1 2 letter code")
+	fs.StringVar(&o.opts.Locale, "locale", "", "locale, for a variant of the language")
+	fs.Func("input-type", "`type` of the input, such as generic-voice or vocon-hybrid, voice types require --language", func(s string) error {
+		o.opts.InputType = v3.InputType(s)
+		if !slices.Contains(v3.InputTypes, o.opts.InputType) {
+			return fmt.Errorf("unknown input type %q", s)
+		}
+		return nil
+	})
+	fs.Func("focus", "`lat,lng` to weight the suggestions towards", func(s string) error {
+		focus, err := parseCoordinates(s)
+		o.opts.Focus = &focus
+		return err
+	})
+	fs.Func("clip-to-country", "comma separated `countries`, as ISO 3166-1 alpha-2 codes", func(s string) error {
+		o.opts.ClipToCountry = strings.Split(s, ",")
+		return nil
+	})
+	fs.Func("clip-to-bounding-box", "`south_lat,west_lng,north_lat,east_lng` to restrict the suggestions to", func(s string) error {
+		box, err := parseBoundingBox(s)
+		o.opts.ClipToBoundingBox = &box
+		return err
+	})
+	fs.Func("clip-to-circle", "`lat,lng,radius_km` to restrict the suggestions to", func(s string) error {
+		values, err := parseFloats(s, 3)
+		if err != nil {
+			return err
+		}
+		o.opts.ClipToCircle = &v3.Circle{
+			Center:   core.Coordinates{Lat: values[0], Lng: values[1]},
+			RadiusKm: values[2],
+		}
+		return nil
+	})
+	fs.Func("clip-to-polygon", "closed polygon `lat,lng,lat,lng,...` to restrict the suggestions to", func(s string) error {
+		values, err := parseFloats(s, -1)
+		if err != nil {
+			return err
+		}
+		if len(values)%2 != 0 {
+			return fmt.Errorf("odd number of values in %q", s)
+		}
+		o.opts.ClipToPolygon = o.opts.ClipToPolygon[:0]
+		for i := 0; i < len(values); i += 2 {
+			o.opts.ClipToPolygon = append(o.opts.ClipToPolygon, core.Coordinates{Lat: values[i], Lng: values[i+1]})
+		}
+		return nil
+	})
+	fs.Func("prefer-land", "prefer suggestions on land, true or false (default true)", func(s string) error {
+		preferLand, err := strconv.ParseBool(s)
+		o.opts.PreferLand = core.Bool(preferLand)
+		return err
+	})
+	fs.Func("n-results", "`number` of suggestions to return, up to 100", func(s string) error {
+		n, err := strconv.Atoi(s)
+		o.opts.NResults = core.Int(n)
+		return err
+	})
+	fs.Func("n-focus-results", "`number` of suggestions weighted by the focus", func(s string) error {
+		n, err := strconv.Atoi(s)
+		o.opts.NFocusResult = core.Int(n)
+		return err
+	})
+	fs.BoolVar(&o.withCoordinates, "with-coordinates", false, "include the coordinates of the suggestions, which is billed as a conversion")
+	return o
+}
+
+func runAutosuggest(ctx context.Context, c *common, opts any, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	o := opts.(*autosuggestOptions)
+	if c.format == formatGeoJSON && !o.withCoordinates {
+		return fmt.Errorf("geojson output requires --with-coordinates")
+	}
+	svc, err := c.service()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+
+	if !o.withCoordinates {
+		resp, err := svc.V3().AutoSuggest(ctx, args[0], &o.opts)
+		if err != nil {
+			return err
+		}
+		if c.format == formatJSON {
+			return writeJSON(c.stdout, resp)
+		}
+		rows := make([][]string, 0, len(resp.Suggestions))
+		for _, s := range resp.Suggestions {
+			rows = append(rows, suggestionRow(s))
+		}
+		return writeTable(c.stdout, suggestionHeader, rows)
+	}
+
+	resp, err := svc.V3().AutoSuggestWithCoordinates(ctx, args[0], &o.opts)
+	if err != nil {
+		return err
+	}
+	switch c.format {
+	case formatJSON:
+		return writeJSON(c.stdout, resp)
+	case formatGeoJSON:
+		collection := featureCollection{Type: "FeatureCollection", Features: []feature{}}
+		for _, s := range resp.Suggestions {
+			collection.Features = append(collection.Features, pointFeature(s.Coordinates, map[string]any{
+				"words":        s.Words,
+				"country":      s.Country,
+				"nearestPlace": s.NearestPlace,
+				"rank":         s.Rank,
+				"language":     s.Language,
+				"map":          s.MapURL,
+			}))
+		}
+		return writeJSON(c.stdout, collection)
+	}
+	rows := make([][]string, 0, len(resp.Suggestions))
+	for _, s := range resp.Suggestions {
+		rows = append(rows, append(suggestionRow(s.AutoSuggestSuggestion), formatCoordinates(s.Coordinates)))
+	}
+	return writeTable(c.stdout, append(suggestionHeader, "COORDINATES"), rows)
+}
+
+var suggestionHeader = []string{"RANK", "WORDS", "COUNTRY", "NEAREST PLACE", "DISTANCE (KM)", "LANGUAGE"}
+
+func suggestionRow(s v3.AutoSuggestSuggestion) []string {
+	return []string{strconv.Itoa(s.Rank), s.Words, s.Country, s.NearestPlace, strconv.Itoa(s.DistanceToFocusKm), s.Language}
+}
+
+func runGridSection(ctx context.Context, c *common, _ any, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	box, err := parseBoundingBox(args[0])
+	if err != nil {
+		return err
+	}
+	svc, err := c.service()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	if c.format == formatGeoJSON {
+		resp, err := svc.V3().GridSectionGeoJson(ctx, box)
+		if err != nil {
+			return err
+		}
+		return writeJSON(c.stdout, resp)
+	}
+	resp, err := svc.V3().GridSection(ctx, box)
+	if err != nil {
+		return err
+	}
+	if c.format == formatJSON {
+		return writeJSON(c.stdout, resp)
+	}
+	rows := make([][]string, 0, len(resp.Lines))
+	for _, line := range resp.Lines {
+		rows = append(rows, []string{formatCoordinates(line.Start), formatCoordinates(line.End)})
+	}
+	return writeTable(c.stdout, []string{"START", "END"}, rows)
+}
+
+func runLanguages(ctx context.Context, c *common, _ any, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	if c.format == formatGeoJSON {
+		return errNoGeoJSON
+	}
+	svc, err := c.service()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	resp, err := svc.V3().AvailableLanguages(ctx)
+	if err != nil {
+		return err
+	}
+	if c.format == formatJSON {
+		return writeJSON(c.stdout, resp)
+	}
+	rows := make([][]string, 0, len(resp.Languages))
+	for _, language := range resp.Languages {
+		rows = append(rows, []string{language.Code, language.Name, language.NativeName})
+		for _, locale := range language.Locales {
+			rows = append(rows, []string{locale.Code, locale.Name, locale.NativeName})
+		}
+	}
+	return writeTable(c.stdout, []string{"CODE", "NAME", "NATIVE NAME"}, rows)
+}
+
+func runFind(_ context.Context, c *common, _ any, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	if c.format == formatGeoJSON {
+		return errNoGeoJSON
+	}
+	text, err := io.ReadAll(c.stdin)
+	if err != nil {
+		return err
+	}
+	// Finding addresses is done locally, without calling the API.
+	found := w3w.NewService(c.key).FindPossible3wa(string(text))
+	if c.format == formatJSON {
+		if found == nil {
+			found = []string{}
+		}
+		return writeJSON(c.stdout, found)
+	}
+	rows := make([][]string, 0, len(found))
+	for _, words := range found {
+		rows = append(rows, []string{words})
+	}
+	return writeTable(c.stdout, []string{"WORDS"}, rows)
+}
+
+// validation is the result of validating a three word address.
+type validation struct {
+	Words string `json:"words"`
+	Valid bool   `json:"valid"`
+}
+
+func runValidate(ctx context.Context, c *common, _ any, args []string) error {
+	if c.format == formatGeoJSON {
+		return errNoGeoJSON
+	}
+	inputs := args
+	if len(inputs) == 0 {
+		scanner := bufio.NewScanner(c.stdin)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				inputs = append(inputs, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+	svc, err := c.service()
+	if err != nil {
+		return err
+	}
+
+	results := make([]validation, 0, len(inputs))
+	invalid := false
+	for _, input := range inputs {
+		words := strings.TrimPrefix(input, "///")
+		valid, err := isValid(ctx, c, svc, words)
+		if err != nil {
+			return fmt.Errorf("%s: %w", words, err)
+		}
+		results = append(results, validation{Words: words, Valid: valid})
+		invalid = invalid || !valid
+	}
+
+	if c.format == formatJSON {
+		err = writeJSON(c.stdout, results)
+	} else {
+		rows := make([][]string, 0, len(results))
+		for _, result := range results {
+			rows = append(rows, []string{result.Words, strconv.FormatBool(result.Valid)})
+		}
+		err = writeTable(c.stdout, []string{"WORDS", "VALID"}, rows)
+	}
+	if err != nil {
+		return err
+	}
+	if invalid {
+		return errInvalid
+	}
+	return nil
+}
+
+// isValid checks words is an existing three word address. Unlike
+// Service.IsValid3wa, failures to reach the API are returned rather than
+// reported as invalid addresses.
+func isValid(ctx context.Context, c *common, svc w3w.Service, words string) (bool, error) {
+	if !svc.IsPossible3wa(words) {
+		return false, nil
+	}
+	ctx, cancel := c.context(ctx)
+	defer cancel()
+	resp, err := svc.V3().AutoSuggest(ctx, words, &v3.AutoSuggestOpts{NResults: core.Int(1)})
+	if err != nil {
+		return false, err
+	}
+	return len(resp.Suggestions) > 0 && resp.Suggestions[0].Words == words, nil
+}
+
+// parseFloats parses comma separated numbers, expecting n of them unless n is -1.
+func parseFloats(s string, n int) ([]float64, error) {
+	parts := strings.Split(s, ",")
+	if n >= 0 && len(parts) != n {
+		return nil, fmt.Errorf("expected %d comma separated numbers, got %q", n, s)
+	}
+	values := make([]float64, len(parts))
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", part)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func parseCoordinates(s string) (core.Coordinates, error) {
+	values, err := parseFloats(s, 2)
+	if err != nil {
+		return core.Coordinates{}, err
+	}
+	return core.Coordinates{Lat: values[0], Lng: values[1]}, nil
+}
+
+func parseBoundingBox(s string) (v3.BoundingBox, error) {
+	values, err := parseFloats(s, 4)
+	if err != nil {
+		return v3.BoundingBox{}, err
+	}
+	return v3.BoundingBox{
+		SouthWest: core.Coordinates{Lat: values[0], Lng: values[1]},
+		NorthEast: core.Coordinates{Lat: values[2], Lng: values[3]},
+	}, nil
+}
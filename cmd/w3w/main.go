@@ -0,0 +1,186 @@
+// Command w3w is a command-line client for the what3words API.
+//
+// Usage:
+//
+//	w3w <command> [flags] [arguments]
+//
+// The commands are:
+//
+//	convert-to-3wa          convert coordinates to three word addresses
+//	convert-to-coordinates  convert three word addresses to coordinates
+//	autosuggest             suggest three word addresses for a partial input
+//	grid-section            print the grid lines within a bounding box
+//	languages               list the available languages
+//	find                    find possible three word addresses in stdin
+//	validate                check three word addresses exist
+//
+// Every command accepts the following flags:
+//
+//	--key       API key, defaults to the W3W_API_KEY environment variable
+//	--base-url  base URL of the API, for example an enterprise server
+//	--format    output format: json, geojson or table
+//	--timeout   timeout of each request
+//
+// Run `w3w <command> --help` for the flags and arguments of a command. Use
+// `--` before arguments starting with a minus sign, such as negative latitudes:
+//
+//	w3w convert-to-3wa --format table -- -33.8568,151.2153
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	w3w "github.com/what3words/w3w-go-wrapper"
+)
+
+// envAPIKey is the environment variable holding the API key.
+const envAPIKey = "W3W_API_KEY"
+
+// errUsage reports invalid flags or arguments, the usage of the command
+// has already been printed.
+var errUsage = errors.New("usage")
+
+// env holds what a command needs from its environment, so that it can
+// be run from tests.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+}
+
+// command is a subcommand of w3w.
+type command struct {
+	name  string
+	usage string
+	short string
+	// flags adds the flags specific to the command.
+	flags func(fs *flag.FlagSet) any
+	// run runs the command with the parsed common and specific flags.
+	run func(ctx context.Context, c *common, opts any, args []string) error
+}
+
+var commands = map[string]command{}
+
+func register(cmd command) {
+	commands[cmd.name] = cmd
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], env{
+		stdin:  os.Stdin,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		getenv: os.Getenv,
+	}))
+}
+
+// run runs the command line and returns the exit code: 0 on success, 1 when
+// the command failed, 2 on usage errors and 3 when validate found invalid
+// addresses.
+func run(args []string, e env) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(e.stderr)
+		return 2
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(e.stderr, "w3w: unknown command %q\n\n", args[0])
+		usage(e.stderr)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("w3w "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: w3w %s [flags] %s\n\n%s.\n\nFlags:\n", cmd.name, cmd.usage, cmd.short)
+		fs.PrintDefaults()
+	}
+	c := &common{env: e}
+	fs.StringVar(&c.key, "key", "", "API key, defaults to the "+envAPIKey+" environment variable")
+	fs.StringVar(&c.baseURL, "base-url", "", "base URL of the API, for example an enterprise server")
+	fs.StringVar(&c.format, "format", formatJSON, "output format: json, geojson or table")
+	fs.DurationVar(&c.timeout, "timeout", 30*time.Second, "timeout of each request")
+	var opts any
+	if cmd.flags != nil {
+		opts = cmd.flags(fs)
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	switch c.format {
+	case formatJSON, formatGeoJSON, formatTable:
+	default:
+		fmt.Fprintf(e.stderr, "w3w %s: invalid format %q, must be json, geojson or table\n", cmd.name, c.format)
+		return 2
+	}
+	if c.key == "" {
+		c.key = e.getenv(envAPIKey)
+	}
+
+	err := cmd.run(context.Background(), c, opts, fs.Args())
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errUsage):
+		fs.Usage()
+		return 2
+	case errors.Is(err, errInvalid):
+		return 3
+	}
+	fmt.Fprintf(e.stderr, "w3w %s: %v\n", cmd.name, err)
+	return 1
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: w3w <command> [flags] [arguments]\n\nCommands:\n")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-24s%s\n", name, commands[name].short)
+	}
+	fmt.Fprintf(w, "\nRun 'w3w <command> --help' for the flags of a command.\n")
+}
+
+// common holds the flags shared by every command.
+type common struct {
+	env
+	key     string
+	baseURL string
+	format  string
+	timeout time.Duration
+}
+
+// service creates the what3words service configured by the common flags.
+func (c *common) service() (w3w.Service, error) {
+	if c.key == "" {
+		return nil, fmt.Errorf("missing API key, set --key or %s", envAPIKey)
+	}
+	var opts []w3w.ServiceOpts
+	if c.baseURL != "" {
+		opts = append(opts, w3w.WithCustomBaseURL(strings.TrimSuffix(c.baseURL, "/")))
+	}
+	return w3w.NewService(c.key, opts...), nil
+}
+
+// context returns a context bounded by the timeout flag.
+func (c *common) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
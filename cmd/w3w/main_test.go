@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+	"github.com/what3words/w3w-go-wrapper/pkg/w3wtest"
+)
+
+// cliServer answers the endpoints used by the tests, recording the query
+// of each request.
+func cliServer(t *testing.T, queries *[]url.Values) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "env-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"code":"InvalidKey","message":"Authentication failed"}}`))
+			return
+		}
+		if queries != nil {
+			*queries = append(*queries, r.URL.Query())
+		}
+		switch r.URL.Path {
+		case "/v3/convert-to-3wa":
+			w.Write([]byte(`{"country":"GB","nearestPlace":"London","coordinates":{"lat":51.520847,"lng":-0.195521},"words":"filled.count.soap","language":"en"}`))
+		case "/v3/autosuggest":
+			if r.URL.Query().Get("input") == "filled.count.soap" {
+				w.Write([]byte(`{"suggestions":[{"country":"GB","nearestPlace":"London","words":"filled.count.soap","rank":1,"language":"en"}]}`))
+				return
+			}
+			w.Write([]byte(`{"suggestions":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func runCLI(t *testing.T, stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, env{
+		stdin:  strings.NewReader(stdin),
+		stdout: &stdout,
+		stderr: &stderr,
+		getenv: func(name string) string {
+			if name == envAPIKey {
+				return "env-key"
+			}
+			return ""
+		},
+	})
+	return code, stdout.String(), stderr.String()
+}
+
+func TestConvertTo3waTable(t *testing.T) {
+	srv := cliServer(t, nil)
+	code, stdout, stderr := runCLI(t, "", "convert-to-3wa", "--base-url", srv.URL, "--format", "table", "51.520847,-0.195521")
+	if code != 0 {
+		t.Fatalf("ERROR: Expected exit code 0, got %d: %s", code, stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "WORDS") || !strings.HasPrefix(lines[1], "filled.count.soap") {
+		t.Fatalf("ERROR: Unexpected table output:\n%s", stdout)
+	}
+	if !strings.Contains(lines[1], "51.520847,-0.195521") {
+		t.Fatalf("ERROR: Expected the coordinates in the table, got:\n%s", stdout)
+	}
+}
+
+func TestKeyFlagOverridesEnvironment(t *testing.T) {
+	srv := cliServer(t, nil)
+	code, _, stderr := runCLI(t, "", "convert-to-3wa", "--base-url", srv.URL, "--key", "other-key", "51.520847,-0.195521")
+	if code != 1 {
+		t.Fatalf("ERROR: Expected exit code 1, got %d", code)
+	}
+	if !strings.Contains(stderr, "Authentication failed") {
+		t.Fatalf("ERROR: Expected the API error on stderr, got %q", stderr)
+	}
+}
+
+func TestAutosuggestFlags(t *testing.T) {
+	var queries []url.Values
+	srv := cliServer(t, &queries)
+	code, stdout, stderr := runCLI(t, "", "autosuggest", "--base-url", srv.URL,
+		"--focus", "51.52,-0.19",
+		"--clip-to-country", "GB,BE",
+		"--clip-to-circle", "51.52,-0.19,10",
+		"--n-results", "3",
+		"--prefer-land", "false",
+		"filled.count.soap",
+	)
+	if code != 0 {
+		t.Fatalf("ERROR: Expected exit code 0, got %d: %s", code, stderr)
+	}
+	var resp struct {
+		Suggestions []struct{ Words string }
+	}
+	if err := json.Unmarshal([]byte(stdout), &resp); err != nil || len(resp.Suggestions) != 1 {
+		t.Fatalf("ERROR: Expected a JSON response with one suggestion, got %q (%v)", stdout, err)
+	}
+	if len(queries) != 1 {
+		t.Fatalf("ERROR: Expected 1 request, got %d", len(queries))
+	}
+	expected := map[string]string{
+		"focus":           "51.520000,-0.190000",
+		"clip-to-country": "GB,BE",
+		"clip-to-circle":  "51.520000,-0.190000,10.000000",
+		"n-results":       "3",
+		"prefer-land":     "false",
+	}
+	for name, value := range expected {
+		if got := queries[0].Get(name); got != value {
+			t.Fatalf("ERROR: Expected %s=%q, got %q", name, value, got)
+		}
+	}
+}
+
+func TestValidateExitCode(t *testing.T) {
+	srv := cliServer(t, nil)
+	code, stdout, stderr := runCLI(t, "///filled.count.soap\nfilled.count.soup\n", "validate", "--base-url", srv.URL)
+	if code != 3 {
+		t.Fatalf("ERROR: Expected exit code 3 with an invalid address, got %d: %s", code, stderr)
+	}
+	var results []validation
+	if err := json.Unmarshal([]byte(stdout), &results); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	expected := []validation{{"filled.count.soap", true}, {"filled.count.soup", false}}
+	if len(results) != len(expected) || results[0] != expected[0] || results[1] != expected[1] {
+		t.Fatalf("ERROR: Expected %v, got %v", expected, results)
+	}
+
+	code, stdout, stderr = runCLI(t, "", "validate", "--key", "bogus", "--base-url", srv.URL, "filled.count.soap")
+	if code != 1 || stdout != "" || !strings.Contains(stderr, "Authentication failed") {
+		t.Fatalf("ERROR: Expected API errors to be reported with exit code 1, got %d with %q and %q", code, stdout, stderr)
+	}
+}
+
+func TestFindDoesNotNeedKey(t *testing.T) {
+	var stdout bytes.Buffer
+	code := run([]string{"find", "--format", "table"}, env{
+		stdin:  strings.NewReader("Deliver to ///filled.count.soap or index.home.raft."),
+		stdout: &stdout,
+		stderr: &bytes.Buffer{},
+		getenv: func(string) string { return "" },
+	})
+	if code != 0 {
+		t.Fatalf("ERROR: Expected exit code 0, got %d", code)
+	}
+	if stdout.String() != "WORDS\nfilled.count.soap\nindex.home.raft\n" {
+		t.Fatalf("ERROR: Unexpected output %q", stdout.String())
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	tests := [][]string{
+		{},
+		{"unknown"},
+		{"convert-to-3wa"},
+		{"languages", "--format", "xml"},
+		{"autosuggest", "--n-results", "many", "index.home.raft"},
+		{"autosuggest", "--input-type", "morse", "index.home.raft"},
+	}
+	for _, args := range tests {
+		if code, _, _ := runCLI(t, "", args...); code != 2 {
+			t.Fatalf("ERROR: Expected exit code 2 for %v, got %d", args, code)
+		}
+	}
+}
+
+func TestConvertOutputMatchesUpstream(t *testing.T) {
+	srv := w3wtest.NewServer()
+	defer srv.Close()
+	words := w3wtest.Words(core.Coordinates{Lat: 51.520847, Lng: -0.195521})
+	tests := []struct {
+		args  []string
+		path  string
+		query url.Values
+	}{
+		{
+			args:  []string{"convert-to-3wa", "--format", "geojson", "51.520847,-0.195521"},
+			path:  "/v3/convert-to-3wa",
+			query: url.Values{"coordinates": {"51.520847,-0.195521"}, "format": {"geojson"}},
+		},
+		{
+			args:  []string{"convert-to-coordinates", "--format", "geojson", words},
+			path:  "/v3/convert-to-coordinates",
+			query: url.Values{"words": {words}, "format": {"geojson"}},
+		},
+		{
+			args:  []string{"convert-to-coordinates", "--language", "de", "--locale", "de_at", words},
+			path:  "/v3/convert-to-coordinates",
+			query: url.Values{"words": {words}, "language": {"de"}, "locale": {"de_at"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(strings.Join(test.args, " "), func(t *testing.T) {
+			args := append([]string{test.args[0], "--base-url", srv.URL}, test.args[1:]...)
+			code, stdout, stderr := runCLI(t, "", args...)
+			if code != 0 {
+				t.Fatalf("ERROR: Expected exit code 0, got %d: %s", code, stderr)
+			}
+			req, _ := http.NewRequest(http.MethodGet, srv.URL+test.path+"?"+test.query.Encode(), nil)
+			req.Header.Set("X-Api-Key", "env-key")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("ERROR: Got error %v", err)
+			}
+			defer resp.Body.Close()
+			var expected, got any
+			if err := json.NewDecoder(resp.Body).Decode(&expected); err != nil {
+				t.Fatalf("ERROR: Got error %v", err)
+			}
+			if err := json.Unmarshal([]byte(stdout), &got); err != nil {
+				t.Fatalf("ERROR: Got error %v", err)
+			}
+			if !reflect.DeepEqual(expected, got) {
+				t.Fatalf("ERROR: Expected the upstream payload %v, got %v", expected, got)
+			}
+		})
+	}
+}
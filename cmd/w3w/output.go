@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+// Output formats selected with the --format flag.
+const (
+	formatJSON    = "json"
+	formatGeoJSON = "geojson"
+	formatTable   = "table"
+)
+
+// errInvalid reports that validate found invalid addresses, after printing
+// its output.
+var errInvalid = errors.New("invalid addresses")
+
+// errNoGeoJSON is returned by commands which have no GeoJSON representation.
+var errNoGeoJSON = errors.New("geojson output is not supported by this command")
+
+// writeJSON writes v as indented JSON.
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
+
+// writeTable writes the rows as columns aligned under the header.
+func writeTable(w io.Writer, header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// formatFloat formats a coordinate without trailing zeros.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// formatCoordinates formats coordinates as lat,lng.
+func formatCoordinates(c core.Coordinates) string {
+	return formatFloat(c.Lat) + "," + formatFloat(c.Lng)
+}
+
+// featureCollection is a GeoJSON FeatureCollection built by the CLI for
+// results the API does not return as GeoJSON.
+type featureCollection struct {
+	Type     string    `json:"type"`
+	Features []feature `json:"features"`
+}
+
+type feature struct {
+	Type       string         `json:"type"`
+	Geometry   geometry       `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+type geometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// pointFeature returns a GeoJSON Point feature at the coordinates.
+func pointFeature(c core.Coordinates, properties map[string]any) feature {
+	return feature{
+		Type:       "Feature",
+		Geometry:   geometry{Type: "Point", Coordinates: []float64{c.Lng, c.Lat}},
+		Properties: properties,
+	}
+}
@@ -10,3 +10,12 @@ import (
 type HttpClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
+
+// HttpClientFunc is an adapter allowing the use of an ordinary
+// function as an HttpClient.
+type HttpClientFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req).
+func (f HttpClientFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
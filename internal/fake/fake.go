@@ -0,0 +1,212 @@
+// Package fake holds the scripting and recording shared by the fakes of
+// the v3fake and w3wfake packages.
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ErrUnscripted is returned by calls to a method without scripted result.
+var ErrUnscripted = errors.New("fake: no result scripted")
+
+// Call is a call made to a method of a fake.
+type Call struct {
+	// Method called, such as ConvertTo3wa.
+	Method string
+	// Input of the call, such as the coordinates, words or bounding box,
+	// nil for methods without input.
+	Input any
+	// Opts passed to the call, such as *v3.ConvertAPIOpts, if any.
+	Opts any
+	// Time the call was made.
+	Time time.Time
+}
+
+func (c Call) String() string {
+	if c.Input == nil {
+		return c.Method + "()"
+	}
+	return fmt.Sprintf("%s(%v)", c.Method, c.Input)
+}
+
+// Result is a scripted result of a method.
+type Result struct {
+	Value any
+	Err   error
+	Delay time.Duration
+}
+
+// stub holds the results scripted for a method and input. Results are
+// returned in order, the last one being repeated.
+type stub struct {
+	results []Result
+	next    int
+}
+
+type stubKey struct {
+	method string
+	input  any
+}
+
+// anyInput is the key of stubs matching every input of a method.
+type anyInput struct{}
+
+// Registry scripts the results of the methods of a fake and records
+// the calls made to them. It is safe for concurrent use.
+type Registry struct {
+	mu      sync.Mutex
+	stubs   map[stubKey]*stub
+	latency map[string]time.Duration
+	calls   []Call
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		stubs:   make(map[stubKey]*stub),
+		latency: make(map[string]time.Duration),
+	}
+}
+
+// Add appends the result to the results of the method for each of the
+// inputs, or for any input when none is given.
+func (r *Registry) Add(method string, inputs []any, result Result) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(inputs) == 0 {
+		inputs = []any{anyInput{}}
+	}
+	for _, input := range inputs {
+		key := stubKey{method, input}
+		if r.stubs[key] == nil {
+			r.stubs[key] = &stub{}
+		}
+		r.stubs[key].results = append(r.stubs[key].results, result)
+	}
+}
+
+// SetLatency delays every call to the method by d, or every call to any
+// method when method is empty.
+func (r *Registry) SetLatency(method string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latency[method] = d
+}
+
+// Reset forgets the scripted results, latencies and recorded calls.
+func (r *Registry) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stubs = make(map[stubKey]*stub)
+	r.latency = make(map[string]time.Duration)
+	r.calls = nil
+}
+
+// Call records the call and returns its scripted result, after waiting for
+// the latency of the method and the delay of the result. Results scripted for
+// the input take precedence over results scripted for any input. scripted is
+// false, and err wraps ErrUnscripted, when no result was scripted.
+func (r *Registry) Call(ctx context.Context, method string, input, opts any) (value any, scripted bool, err error) {
+	r.mu.Lock()
+	call := Call{Method: method, Input: input, Opts: opts, Time: time.Now()}
+	r.calls = append(r.calls, call)
+	s := r.stubs[stubKey{method, input}]
+	if s == nil {
+		s = r.stubs[stubKey{method, anyInput{}}]
+	}
+	var res Result
+	if s != nil {
+		res = s.results[s.next]
+		if s.next < len(s.results)-1 {
+			s.next++
+		}
+	}
+	delay := r.latency[""] + r.latency[method] + res.Delay
+	r.mu.Unlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil, true, ctx.Err()
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, true, err
+	}
+	if s == nil {
+		return nil, false, fmt.Errorf("%w for %s", ErrUnscripted, call)
+	}
+	return res.Value, true, res.Err
+}
+
+// Calls returns the calls made so far, in order, to the given methods or
+// to any method when none is given.
+func (r *Registry) Calls(methods ...string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var calls []Call
+	for _, call := range r.calls {
+		if len(methods) == 0 || contains(methods, call.Method) {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// AssertCalled fails the test unless the method was called, with one of the
+// inputs when any is given.
+func (r *Registry) AssertCalled(t testing.TB, method string, inputs ...any) {
+	t.Helper()
+	for _, call := range r.Calls(method) {
+		if len(inputs) == 0 || containsInput(inputs, call.Input) {
+			return
+		}
+	}
+	if len(inputs) == 0 {
+		t.Errorf("expected a call to %s, got calls %v", method, r.Calls())
+		return
+	}
+	t.Errorf("expected a call to %s with %v, got calls %v", method, inputs, r.Calls())
+}
+
+// AssertNotCalled fails the test if the method was called.
+func (r *Registry) AssertNotCalled(t testing.TB, method string) {
+	t.Helper()
+	if calls := r.Calls(method); len(calls) > 0 {
+		t.Errorf("expected no call to %s, got %v", method, calls)
+	}
+}
+
+// AssertCallCount fails the test unless the method was called n times.
+func (r *Registry) AssertCallCount(t testing.TB, method string, n int) {
+	t.Helper()
+	if calls := r.Calls(method); len(calls) != n {
+		t.Errorf("expected %d calls to %s, got %d: %v", n, method, len(calls), calls)
+	}
+}
+
+func containsInput(inputs []any, input any) bool {
+	for _, in := range inputs {
+		if in == input {
+			return true
+		}
+	}
+	return false
+}
@@ -5,33 +5,69 @@ import (
 	"fmt"
 	"maps"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/what3words/w3w-go-wrapper/internal/client"
 	"github.com/what3words/w3w-go-wrapper/internal/version"
 	"github.com/what3words/w3w-go-wrapper/pkg/core"
 )
 
+// Names of the v3 endpoints, used to identify an endpoint when configuring
+// per endpoint behaviour such as rate limits.
+const (
+	EndpointConvertTo3wa               = "convert-to-3wa"
+	EndpointConvertToCoordinates       = "convert-to-coordinates"
+	EndpointAutoSuggest                = "autosuggest"
+	EndpointAutoSuggestWithCoordinates = "autosuggest-with-coordinates"
+	EndpointAutoSuggestSelection       = "autosuggest-selection"
+	EndpointGridSection                = "grid-section"
+	EndpointAvailableLanguages         = "available-languages"
+)
+
 // API models the What3Words public v3 API. Each endpoint has a corresponding method
 // that returns strictly typed structures or errors. APIOptions can be used to
 // configure or modify the API Controller when creating an instance with the `NewAPI` function.
+// CallOptions, such as WithResponseMetadata, WithCallHeader or WithCallTimeout, can
+// be passed to any endpoint method to configure that single call.
 //
 // # Default Configuration:
 // - `baseURL`: https://api.what3words.com
 // - `headers`: None
 // - `client`: http.DefaultClient
 //
+// The v3fake package provides an in-memory API for tests.
+//
 //go:generate mockery --name API --output ./mocks --outpkg mocks --case underscore
 type API interface {
 	// Configuration Setters
+	//
+	// Setters are safe to call while requests are in flight, requests
+	// already started keep using the configuration they started with.
 
 	// SetBaseURL sets the base URL for the What3Words API after initialization.
 	SetBaseURL(baseURL string)
 	// SetHeader sets a single HTTP header to include in all API requests after initialization.
 	SetHeader(headerKey, headerValue string)
-	// SetHeaderMap sets multiple HTTP headers at once for all API requests after initialization.
+	// SetHeaderMap sets multiple HTTP headers at once for all API requests after initialization,
+	// replacing the headers previously set. The API key and wrapper version headers are kept
+	// unless present in headers.
 	SetHeaderMap(headers map[string]string)
 	// SetClient sets a custom HTTP client for API requests after initialization.
 	SetClient(client client.HttpClient)
+	// SetRetryPolicy sets the policy used to retry failed API requests after initialization.
+	// Passing nil disables retries.
+	SetRetryPolicy(policy *core.RetryPolicy)
+	// AddMiddleware appends middlewares wrapping the HTTP client after initialization.
+	AddMiddleware(mw ...Middleware)
+	// Clone returns a copy of the API with the given options applied, leaving
+	// this API unchanged. Rate limiters, caches, request deduplication, the
+	// circuit breaker and the endpoint pool are shared with the copy.
+	Clone(opts ...APIOption) API
 
 	// Endpoints
 
@@ -39,18 +75,18 @@ type API interface {
 	// It also provides additional information such as country, grid square bounds,
 	// a nearby place, and a link to the map site.
 	// Returns the response in JSON format.
-	ConvertTo3wa(ctx context.Context, coordinates core.Coordinates, opts *ConvertAPIOpts) (*ConvertAPIJsonResponse, error)
+	ConvertTo3wa(ctx context.Context, coordinates core.Coordinates, opts *ConvertAPIOpts, callOpts ...CallOption) (*ConvertAPIJsonResponse, error)
 	// ConvertTo3waGeoJson performs the same conversion as `ConvertTo3wa` but
 	// returns the response in GeoJSON format.
-	ConvertTo3waGeoJson(ctx context.Context, coordinates core.Coordinates, opts *ConvertAPIOpts) (*ConvertAPIGeoJsonResponse, error)
+	ConvertTo3waGeoJson(ctx context.Context, coordinates core.Coordinates, opts *ConvertAPIOpts, callOpts ...CallOption) (*ConvertAPIGeoJsonResponse, error)
 	// ConvertToCoordinates wraps around /v3/convert-to-coordinates which will
 	// convert a 3 word address to a latitude and longitude pair. It also returns
 	// country, the bounds of the grid square, a nearest place (such as a local town)
 	// and a link to our map site. Returns the response in the JSON format.
-	ConvertToCoordinates(ctx context.Context, words string, opts *ConvertAPIOpts) (*ConvertAPIJsonResponse, error)
+	ConvertToCoordinates(ctx context.Context, words string, opts *ConvertAPIOpts, callOpts ...CallOption) (*ConvertAPIJsonResponse, error)
 	// ConvertTo3waGeoJson performs the same conversion as `ConvertToCoordinates` but
 	// returns the response in GeoJSON format.
-	ConvertToCoordinatesGeoJson(ctx context.Context, words string, opts *ConvertAPIOpts) (*ConvertAPIGeoJsonResponse, error)
+	ConvertToCoordinatesGeoJson(ctx context.Context, words string, opts *ConvertAPIOpts, callOpts ...CallOption) (*ConvertAPIGeoJsonResponse, error)
 	// GridSection wraps around the /v3/grid-section endpoint, returning a section
 	// of the 3m x 3m What3Words grid for a specified bounding box.
 	//
@@ -59,7 +95,7 @@ type API interface {
 	// - `NorthEast` (latitude and longitude of the top-right corner).
 
 	// Response is returned in the JSON format.
-	GridSection(ctx context.Context, boundingBox BoundingBox) (*GridSectionJsonResponse, error)
+	GridSection(ctx context.Context, boundingBox BoundingBox, callOpts ...CallOption) (*GridSectionJsonResponse, error)
 	// GridSectionGeoJson wraps around the /v3/grid-section endpoint, returning a section
 	// of the 3m x 3m What3Words grid for a specified bounding box in GeoJSON format.
 	//
@@ -69,7 +105,7 @@ type API interface {
 	//
 	// GeoJSON format is particularly useful for rendering on maps or integrating
 	// with GIS tools, as it provides structured geospatial data.
-	GridSectionGeoJson(ctx context.Context, boundingBox BoundingBox) (*GridSectionGeoJsonResponse, error)
+	GridSectionGeoJson(ctx context.Context, boundingBox BoundingBox, callOpts ...CallOption) (*GridSectionGeoJsonResponse, error)
 	// AutoSuggest wraps around /v3/autosuggest endpoint which takes slightly
 	// incorrect 3 word address and suggest a list of valid 3 word addresses.
 	// It has powerful features that can, for example, optionally limit results
@@ -108,56 +144,169 @@ type API interface {
 	// to help the API in situations where the input is particularly messy. For normal text input,
 	// the language parameter is optional, and AutoSuggest will work well even without a language parameter.
 	// However, for voice input the language should always be specified.
-	AutoSuggest(ctx context.Context, input string, opts *AutoSuggestOpts) (*AutoSuggestResponse, error)
+	AutoSuggest(ctx context.Context, input string, opts *AutoSuggestOpts, callOpts ...CallOption) (*AutoSuggestResponse, error)
 	// AutoSuggestWithCoordinates provides functionality similar to AutoSuggest, 
 	// with the added feature of including coordinates for each suggestion in the response.
 	//
 	// This method is particularly useful when detailed location information is required
 	// along with the suggested 3 word addresses. It returns an enriched response,
 	// making it easier to associate suggestions with their precise geographic locations.
-	AutoSuggestWithCoordinates(ctx context.Context, input string, opts *AutoSuggestOpts) (*AutoSuggestWithCoordinatesResponse, error)
+	AutoSuggestWithCoordinates(ctx context.Context, input string, opts *AutoSuggestOpts, callOpts ...CallOption) (*AutoSuggestWithCoordinatesResponse, error)
+	// AutoSuggestSelection wraps around the /v3/autosuggest-selection endpoint,
+	// reporting which of the suggestions returned by AutoSuggest for rawInput
+	// was selected by the user. This feedback improves the ranking of future
+	// suggestions.
+	//
+	// The selection is the suggestion picked by the user, its words and rank
+	// are sent along with the source API, voice when opts has a voice input
+	// type and text otherwise. The opts should be the options passed to
+	// AutoSuggest, so that the clipping and focus are reported too.
+	// Suggestions returned by AutoSuggestWithCoordinates are selected using
+	// their embedded AutoSuggestSuggestion.
+	AutoSuggestSelection(ctx context.Context, rawInput string, selection AutoSuggestSuggestion, opts *AutoSuggestOpts, callOpts ...CallOption) error
 	// AvailableLanguages wraps around /v3/available-languages which will
 	// retrieve a list of all available 3 word address languages,
 	// including the ISO 3166-1 alpha-2 2 letter code, English name and native name.
 	// Bosnian-Croatian-Montenegrin-Serbian is available using the language code 'oo' with
 	// Cyrillic and Latin locales ('oo_cy' and 'oo_la')
-	AvailableLanguages(ctx context.Context) (*AvailableLanguagesResponse, error)
+	AvailableLanguages(ctx context.Context, callOpts ...CallOption) (*AvailableLanguagesResponse, error)
 }
 
-type api struct {
+// config holds the configuration of an API. It is never modified once in
+// use by an API, setters replace it with an updated copy instead, so that
+// requests in flight keep a consistent view of the configuration.
+type config struct {
 	baseURL string
 	headers map[string]string
 	client  client.HttpClient
+	retry   *core.RetryPolicy
+	// limiter is shared by all endpoints, endpointLimiters apply
+	// an additional limit to individual endpoints.
+	limiter          core.Limiter
+	endpointLimiters map[string]core.Limiter
+	skipValidation   bool
+	middleware       []Middleware
+	log              logConfig
+	cache            Cache
+	cacheTTL         time.Duration
+	squareCache      *SquareCache
+	languageCatalog  *LanguageCatalog
+	dedup            *core.Deduplicator
+	breaker          *core.CircuitBreaker
+	pool             *core.EndpointPool
+}
+
+// protectedHeaders are kept by SetHeaderMap unless explicitly replaced.
+var protectedHeaders = []string{core.HEADER_API_KEY, core.HEADER_WRAPPER}
+
+// setHeaders copies src into dst, replacing headers of dst whose
+// name only differs in case.
+func setHeaders(dst, src map[string]string) {
+	for key, value := range src {
+		for existing := range dst {
+			if strings.EqualFold(existing, key) {
+				delete(dst, existing)
+			}
+		}
+		dst[key] = value
+	}
+}
+
+// clone returns a copy of the configuration which can be modified without
+// affecting c. Limiters, caches, the deduplicator, the breaker and the
+// endpoint pool are shared with c.
+func (c *config) clone() *config {
+	cp := *c
+	cp.headers = maps.Clone(c.headers)
+	cp.endpointLimiters = maps.Clone(c.endpointLimiters)
+	cp.middleware = slices.Clone(c.middleware)
+	cp.log.redactedHeaders = slices.Clone(c.log.redactedHeaders)
+	return &cp
+}
+
+type api struct {
+	// mu serialises updates of cfg, which is read without locking.
+	mu  sync.Mutex
+	cfg atomic.Pointer[config]
+}
+
+// update replaces the configuration with a copy modified by fn.
+func (a *api) update(fn func(*config)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	cfg := a.cfg.Load().clone()
+	fn(cfg)
+	a.cfg.Store(cfg)
 }
 
 func (a *api) SetBaseURL(baseURL string) {
-	a.baseURL = fmt.Sprintf("%s/v3", baseURL)
+	a.update(func(c *config) {
+		c.baseURL = fmt.Sprintf("%s/v3", baseURL)
+	})
 }
 
 func (a *api) SetHeader(headerKey, headerValue string) {
-	a.headers[headerKey] = headerValue
+	a.update(func(c *config) {
+		setHeaders(c.headers, map[string]string{headerKey: headerValue})
+	})
 }
 
 func (a *api) SetHeaderMap(headers map[string]string) {
-	a.headers = headers
+	a.update(func(c *config) {
+		kept := make(map[string]string, len(headers)+len(protectedHeaders))
+		for key, value := range c.headers {
+			if slices.ContainsFunc(protectedHeaders, func(header string) bool { return strings.EqualFold(key, header) }) {
+				kept[key] = value
+			}
+		}
+		setHeaders(kept, headers)
+		c.headers = kept
+	})
 }
 
 func (a *api) SetClient(client client.HttpClient) {
-	a.client = client
+	a.update(func(c *config) {
+		c.client = client
+	})
 }
 
-type APIOption func(*api)
+func (a *api) SetRetryPolicy(policy *core.RetryPolicy) {
+	a.update(func(c *config) {
+		c.retry = policy
+	})
+}
+
+func (a *api) AddMiddleware(mw ...Middleware) {
+	a.update(func(c *config) {
+		c.middleware = append(c.middleware, mw...)
+	})
+}
+
+func (a *api) Clone(opts ...APIOption) API {
+	cfg := a.cfg.Load().clone()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	cp := &api{}
+	cp.cfg.Store(cfg)
+	return cp
+}
+
+// APIOption configures an API, when creating it with NewAPI or deriving
+// a copy with Clone.
+type APIOption func(*config)
 
 // WithCustomHeader sets a custom HTTP header to be included with every request
-// made through this API. This is useful for scenarios like adding authentication
-// tokens or other custom headers required by the API.
+// made through this API, replacing any header whose name only differs in case.
+// This is useful for scenarios like adding authentication tokens or other
+// custom headers required by the API.
 //
 // Example usage:
 //
 //	api := NewAPI("your-api-key", WithCustomHeader("X-Custom-Header", "value"))
 func WithCustomHeader(key, value string) APIOption {
-	return func(vs *api) {
-		vs.headers[key] = value
+	return func(vs *config) {
+		setHeaders(vs.headers, map[string]string{key: value})
 	}
 }
 
@@ -169,8 +318,8 @@ func WithCustomHeader(key, value string) APIOption {
 //
 //	customClient := &http.Client{Timeout: 10 * time.Second}
 //	api := NewAPI("your-api-key", WithClient(customClient))
-func WithClient(client client.HttpClient) func(*api) {
-	return func(vs *api) {
+func WithClient(client client.HttpClient) func(*config) {
+	return func(vs *config) {
 		vs.client = client
 	}
 }
@@ -182,12 +331,92 @@ func WithClient(client client.HttpClient) func(*api) {
 // Example usage:
 //
 //	api := NewAPI("your-api-key", WithCustomBaseURL("https://custom-url.example.com"))
-func WithCustomBaseURL(baseURL string) func(*api) {
-	return func(vs *api) {
+func WithCustomBaseURL(baseURL string) func(*config) {
+	return func(vs *config) {
 		vs.baseURL = fmt.Sprintf("%s/v3", baseURL)
 	}
 }
 
+// WithRetryPolicy enables automatic retries of failed requests for every
+// endpoint. Requests are retried on transport errors and on the status codes
+// listed in the policy, waiting an exponential backoff with jitter between
+// attempts, or the duration requested by the `Retry-After` response header.
+//
+// Example usage:
+//
+//	api := NewAPI("your-api-key", WithRetryPolicy(core.DefaultRetryPolicy()))
+func WithRetryPolicy(policy core.RetryPolicy) APIOption {
+	return func(vs *config) {
+		vs.retry = &policy
+	}
+}
+
+// WithRateLimit limits the rate of requests sent by the API to `rate` requests
+// per second, allowing bursts of up to `burst` requests. The limit is shared by
+// all endpoints and all goroutines using the API. Requests exceeding it block
+// until they are allowed or their context is done.
+//
+// Example usage:
+//
+//	api := NewAPI("your-api-key", WithRateLimit(10, 5))
+func WithRateLimit(rate float64, burst int) APIOption {
+	return func(vs *config) {
+		vs.limiter = core.NewRateLimiter(rate, burst)
+	}
+}
+
+// WithEndpointRateLimit applies an additional rate limit of `rate` requests per
+// second, with bursts of up to `burst` requests, shared by the given endpoints.
+// Requests must satisfy both the endpoint limit and the limit set by
+// WithRateLimit, if any.
+//
+// Example usage:
+//
+//	api := NewAPI("your-api-key",
+//	    WithRateLimit(20, 10),
+//	    WithEndpointRateLimit(5, 5, EndpointAutoSuggest, EndpointAutoSuggestWithCoordinates),
+//	    WithEndpointRateLimit(15, 10, EndpointConvertTo3wa, EndpointConvertToCoordinates),
+//	)
+func WithEndpointRateLimit(rate float64, burst int, endpoints ...string) APIOption {
+	return func(vs *config) {
+		limiter := core.NewRateLimiter(rate, burst)
+		if vs.endpointLimiters == nil {
+			vs.endpointLimiters = make(map[string]core.Limiter)
+		}
+		for _, endpoint := range endpoints {
+			vs.endpointLimiters[endpoint] = limiter
+		}
+	}
+}
+
+// WithoutValidation disables the client side validation of request options
+// performed by every endpoint before a request is sent. Options are then
+// forwarded to the API as they are, and problems are reported by the API.
+//
+// Example usage:
+//
+//	api := NewAPI("your-api-key", WithoutValidation())
+func WithoutValidation() APIOption {
+	return func(vs *config) {
+		vs.skipValidation = true
+	}
+}
+
+// WithRequestDeduplication shares a single in-flight request between
+// identical concurrent calls, made to the same endpoint with the same query
+// parameters and headers, which all receive its result. A caller whose
+// context expires returns early, the shared request is only cancelled once
+// every caller waiting for it has returned.
+//
+// Example usage:
+//
+//	api := NewAPI("your-api-key", WithRequestDeduplication())
+func WithRequestDeduplication() APIOption {
+	return func(vs *config) {
+		vs.dedup = core.NewDeduplicator()
+	}
+}
+
 // NewAPI creates a new What3Words V3 API Controller instance.
 //
 // This function initializes an API controller with the provided API key and
@@ -219,182 +448,333 @@ func NewAPI(apiKey string, opts ...APIOption) API {
 	headers[core.HEADER_API_KEY] = apiKey
 	headers[core.HEADER_WRAPPER] = version.ResolveWrapperHeader()
 	baseURL := core.BASE_URL
-	a := &api{
-		fmt.Sprintf("%s/v3", baseURL),
-		headers,
-		http.DefaultClient,
+	cfg := &config{
+		baseURL: fmt.Sprintf("%s/v3", baseURL),
+		headers: headers,
+		client:  http.DefaultClient,
+		log:     defaultLogConfig(),
 	}
 	for _, opt := range opts {
-		opt(a)
+		opt(cfg)
 	}
+	a := &api{}
+	a.cfg.Store(cfg)
 	return a
 }
 
-func (a api) convertTo3wa(ctx context.Context, coordinates core.Coordinates, opts *ConvertAPIOpts, format string) (*convertAPIResponse, error) {
-	var c2cResponse convertAPIResponse
+func (c *config) convertTo3wa(ctx context.Context, coordinates core.Coordinates, opts *ConvertAPIOpts, format string, call callConfig) (*convertAPIResponse, error) {
 	queryParams := make(map[string]string)
 	queryParams["coordinates"] = coordinates.AsQueryParam()
 	queryParams["format"] = format
 	if opts != nil {
 		maps.Copy(queryParams, opts.asOptionsMap())
 	}
-	err := core.MakeGetRequest(
-		ctx,
-		a.client,
-		a.baseURL,
-		queryParams,
-		a.headers,
-		&c2cResponse,
-		"convert-to-3wa",
-	)
+	err := c.validate(func(ve *ValidationError) {
+		ve.Merge("Coordinates", coordinates.Validate())
+		if opts != nil {
+			ve.Merge("", opts.Validate())
+			c.checkCatalog(ctx, ve, opts.Language, opts.Locale)
+		}
+	})
+	if err != nil {
+		return nil, c.logUnsent(ctx, EndpointConvertTo3wa, queryParams, call, &ResponseMeta{}, err)
+	}
+	useSquareCache := format == "json" && c.squareCache != nil && !call.overridesTarget()
+	if useSquareCache {
+		if call.meta == nil {
+			// The target of the response is needed to store it.
+			call.meta = &ResponseMeta{}
+		}
+		target := core.Target(c.baseURL, c.pool, c.headers)
+		if resp, ok := c.squareCache.lookup(target, coordinates, opts); ok {
+			call.meta.FillCached(c.baseURL, EndpointConvertTo3wa)
+			call.meta.Target = target
+			c.logUnsent(ctx, EndpointConvertTo3wa, queryParams, call, call.meta, nil)
+			return &convertAPIResponse{ConvertAPIJsonResponse: resp}, nil
+		}
+	}
+	var c2cResponse convertAPIResponse
+	err = c.get(ctx, EndpointConvertTo3wa, queryParams, &c2cResponse, call)
 	if err != nil {
 		return nil, err
 	}
+	if useSquareCache {
+		c.squareCache.store(call.meta.Target, c2cResponse.ConvertAPIJsonResponse, opts)
+	}
 	return &c2cResponse, nil
 }
 
-func (a api) ConvertTo3wa(ctx context.Context, coordinates core.Coordinates, opts *ConvertAPIOpts) (*ConvertAPIJsonResponse, error) {
-	resp, err := a.convertTo3wa(ctx, coordinates, opts, "json")
+func (a *api) ConvertTo3wa(ctx context.Context, coordinates core.Coordinates, opts *ConvertAPIOpts, callOpts ...CallOption) (*ConvertAPIJsonResponse, error) {
+	cfg := a.cfg.Load()
+	resp, err := cfg.convertTo3wa(ctx, coordinates, opts, "json", newCallConfig(callOpts))
 	if err != nil {
 		return nil, err
 	}
 	return resp.ConvertAPIJsonResponse, nil
 }
 
-func (a api) ConvertTo3waGeoJson(ctx context.Context, coordinates core.Coordinates, opts *ConvertAPIOpts) (*ConvertAPIGeoJsonResponse, error) {
-	resp, err := a.convertTo3wa(ctx, coordinates, opts, "geojson")
+func (a *api) ConvertTo3waGeoJson(ctx context.Context, coordinates core.Coordinates, opts *ConvertAPIOpts, callOpts ...CallOption) (*ConvertAPIGeoJsonResponse, error) {
+	cfg := a.cfg.Load()
+	resp, err := cfg.convertTo3wa(ctx, coordinates, opts, "geojson", newCallConfig(callOpts))
 	if err != nil {
 		return nil, err
 	}
 	return resp.ConvertAPIGeoJsonResponse, nil
 }
 
-func (a api) convertToCoordinates(ctx context.Context, words string, opts *ConvertAPIOpts, format string) (*convertAPIResponse, error) {
-	var c2cResponse convertAPIResponse
+func (c *config) convertToCoordinates(ctx context.Context, words string, opts *ConvertAPIOpts, format string, call callConfig) (*convertAPIResponse, error) {
 	queryParams := make(map[string]string)
 	queryParams["words"] = words
 	queryParams["format"] = format
 	if opts != nil {
 		maps.Copy(queryParams, opts.asOptionsMap())
 	}
-	err := core.MakeGetRequest(
-		ctx,
-		a.client,
-		a.baseURL,
-		queryParams,
-		a.headers,
-		&c2cResponse,
-		"convert-to-coordinates",
-	)
+	err := c.validate(func(ve *ValidationError) {
+		if opts != nil {
+			ve.Merge("", opts.Validate())
+			c.checkCatalog(ctx, ve, opts.Language, opts.Locale)
+		}
+	})
+	if err != nil {
+		return nil, c.logUnsent(ctx, EndpointConvertToCoordinates, queryParams, call, &ResponseMeta{}, err)
+	}
+	var c2cResponse convertAPIResponse
+	err = c.get(ctx, EndpointConvertToCoordinates, queryParams, &c2cResponse, call)
 	if err != nil {
 		return nil, err
 	}
 	return &c2cResponse, nil
 }
 
-func (a api) ConvertToCoordinates(ctx context.Context, words string, opts *ConvertAPIOpts) (*ConvertAPIJsonResponse, error) {
-	resp, err := a.convertToCoordinates(ctx, words, opts, "json")
+func (a *api) ConvertToCoordinates(ctx context.Context, words string, opts *ConvertAPIOpts, callOpts ...CallOption) (*ConvertAPIJsonResponse, error) {
+	cfg := a.cfg.Load()
+	call := newCallConfig(callOpts)
+	useSquareCache := cfg.squareCache != nil && !call.overridesTarget()
+	if useSquareCache && call.meta == nil {
+		call.meta = &ResponseMeta{}
+	}
+	resp, err := cfg.convertToCoordinates(ctx, words, opts, "json", call)
 	if err != nil {
 		return nil, err
 	}
+	if useSquareCache {
+		cfg.squareCache.store(call.meta.Target, resp.ConvertAPIJsonResponse, opts)
+	}
 	return resp.ConvertAPIJsonResponse, nil
 }
 
-func (a api) ConvertToCoordinatesGeoJson(ctx context.Context, words string, opts *ConvertAPIOpts) (*ConvertAPIGeoJsonResponse, error) {
-	resp, err := a.convertToCoordinates(ctx, words, opts, "geojson")
+func (a *api) ConvertToCoordinatesGeoJson(ctx context.Context, words string, opts *ConvertAPIOpts, callOpts ...CallOption) (*ConvertAPIGeoJsonResponse, error) {
+	cfg := a.cfg.Load()
+	resp, err := cfg.convertToCoordinates(ctx, words, opts, "geojson", newCallConfig(callOpts))
 	if err != nil {
 		return nil, err
 	}
 	return resp.ConvertAPIGeoJsonResponse, nil
 }
 
-func (a api) AutoSuggest(ctx context.Context, input string, opts *AutoSuggestOpts) (*AutoSuggestResponse, error) {
-	var autoSuggest autoSuggestResponse
+func (a *api) AutoSuggest(ctx context.Context, input string, opts *AutoSuggestOpts, callOpts ...CallOption) (*AutoSuggestResponse, error) {
+	cfg := a.cfg.Load()
+	call := newCallConfig(callOpts)
 	queryParams := make(map[string]string)
 	queryParams["input"] = input
 	if opts != nil {
 		mOpts := opts.asOptionsMap()
 		maps.Copy(queryParams, mOpts)
 	}
-	err := core.MakeGetRequest(
-		ctx,
-		a.client,
-		a.baseURL,
-		queryParams,
-		a.headers,
-		&autoSuggest,
-		"autosuggest",
-	)
+	err := cfg.validate(func(ve *ValidationError) {
+		if opts != nil {
+			ve.Merge("", opts.Validate())
+			cfg.checkCatalog(ctx, ve, opts.Language, opts.Locale)
+		}
+	})
+	if err != nil {
+		return nil, cfg.logUnsent(ctx, EndpointAutoSuggest, queryParams, call, &ResponseMeta{}, err)
+	}
+	var autoSuggest autoSuggestResponse
+	err = cfg.get(ctx, EndpointAutoSuggest, queryParams, &autoSuggest, call)
 	if err != nil {
 		return nil, err
 	}
 	return &autoSuggest.AutoSuggestResponse, nil
 }
 
-func (a api) AutoSuggestWithCoordinates(ctx context.Context, input string, opts *AutoSuggestOpts) (*AutoSuggestWithCoordinatesResponse, error) {
-	var autoSuggest autoSuggestWithCoordinatesResponse
+func (a *api) AutoSuggestWithCoordinates(ctx context.Context, input string, opts *AutoSuggestOpts, callOpts ...CallOption) (*AutoSuggestWithCoordinatesResponse, error) {
+	cfg := a.cfg.Load()
+	call := newCallConfig(callOpts)
 	queryParams := make(map[string]string)
 	queryParams["input"] = input
 	if opts != nil {
 		mOpts := opts.asOptionsMap()
 		maps.Copy(queryParams, mOpts)
 	}
-	err := core.MakeGetRequest(
-		ctx,
-		a.client,
-		a.baseURL,
-		queryParams,
-		a.headers,
-		&autoSuggest,
-		"autosuggest-with-coordinates",
-	)
+	err := cfg.validate(func(ve *ValidationError) {
+		if opts != nil {
+			ve.Merge("", opts.Validate())
+			cfg.checkCatalog(ctx, ve, opts.Language, opts.Locale)
+		}
+	})
+	if err != nil {
+		return nil, cfg.logUnsent(ctx, EndpointAutoSuggestWithCoordinates, queryParams, call, &ResponseMeta{}, err)
+	}
+	var autoSuggest autoSuggestWithCoordinatesResponse
+	err = cfg.get(ctx, EndpointAutoSuggestWithCoordinates, queryParams, &autoSuggest, call)
 	if err != nil {
 		return nil, err
 	}
 	return &autoSuggest.AutoSuggestWithCoordinatesResponse, nil
 }
 
-func (a api) gridSection(ctx context.Context, boundingBox BoundingBox, format string) (*gridSectionResponse, error) {
-	var gridSection gridSectionResponse
+func (a *api) AutoSuggestSelection(ctx context.Context, rawInput string, selection AutoSuggestSuggestion, opts *AutoSuggestOpts, callOpts ...CallOption) error {
+	cfg := a.cfg.Load()
+	call := newCallConfig(callOpts)
+	queryParams := make(map[string]string)
+	queryParams["raw-input"] = rawInput
+	queryParams["selection"] = selection.Words
+	queryParams["rank"] = strconv.Itoa(selection.Rank)
+	queryParams["source-api"] = SourceAPIText
+	if opts != nil {
+		maps.Copy(queryParams, opts.asOptionsMap())
+		queryParams["source-api"] = opts.InputType.sourceAPI()
+	}
+	err := cfg.validate(func(ve *ValidationError) {
+		if rawInput == "" {
+			ve.Add("RawInput", "must not be empty")
+		}
+		ve.Merge("Selection", selection.Validate())
+		if opts != nil {
+			ve.Merge("", opts.Validate())
+			cfg.checkCatalog(ctx, ve, opts.Language, opts.Locale)
+		}
+	})
+	if err != nil {
+		return cfg.logUnsent(ctx, EndpointAutoSuggestSelection, queryParams, call, &ResponseMeta{}, err)
+	}
+	var selectionResponse autoSuggestSelectionResponse
+	return cfg.get(ctx, EndpointAutoSuggestSelection, queryParams, &selectionResponse, call)
+}
+
+func (c *config) gridSection(ctx context.Context, boundingBox BoundingBox, format string, call callConfig) (*gridSectionResponse, error) {
 	queryParams := make(map[string]string)
 	queryParams["bounding-box"] = boundingBox.asQueryParam()
 	queryParams["format"] = format
-	err := core.MakeGetRequest(
-		ctx,
-		a.client,
-		a.baseURL,
-		queryParams,
-		a.headers,
-		&gridSection,
-		"grid-section",
-	)
+	err := c.validate(func(ve *ValidationError) {
+		ve.Merge("BoundingBox", boundingBox.Validate())
+	})
+	if err != nil {
+		return nil, c.logUnsent(ctx, EndpointGridSection, queryParams, call, &ResponseMeta{}, err)
+	}
+	var gridSection gridSectionResponse
+	err = c.get(ctx, EndpointGridSection, queryParams, &gridSection, call)
 	if err != nil {
 		return nil, err
 	}
 	return &gridSection, nil
 }
 
-func (a api) GridSection(ctx context.Context, boundingBox BoundingBox) (*GridSectionJsonResponse, error) {
-	resp, err := a.gridSection(ctx, boundingBox, "json")
+func (a *api) GridSection(ctx context.Context, boundingBox BoundingBox, callOpts ...CallOption) (*GridSectionJsonResponse, error) {
+	cfg := a.cfg.Load()
+	resp, err := cfg.gridSection(ctx, boundingBox, "json", newCallConfig(callOpts))
 	if err != nil {
 		return nil, err
 	}
 	return resp.GridSectionJsonResponse, nil
 }
 
-func (a api) GridSectionGeoJson(ctx context.Context, boundingBox BoundingBox) (*GridSectionGeoJsonResponse, error) {
-	resp, err := a.gridSection(ctx, boundingBox, "geojson")
+func (a *api) GridSectionGeoJson(ctx context.Context, boundingBox BoundingBox, callOpts ...CallOption) (*GridSectionGeoJsonResponse, error) {
+	cfg := a.cfg.Load()
+	resp, err := cfg.gridSection(ctx, boundingBox, "geojson", newCallConfig(callOpts))
 	if err != nil {
 		return nil, err
 	}
 	return resp.GridSectionGeoJsonResponse, nil
 }
 
-func (a api) AvailableLanguages(ctx context.Context) (*AvailableLanguagesResponse, error) {
+func (a *api) AvailableLanguages(ctx context.Context, callOpts ...CallOption) (*AvailableLanguagesResponse, error) {
+	cfg := a.cfg.Load()
+	call := newCallConfig(callOpts)
 	var availableLanguages availableLanguagesResponse
-	err := core.MakeGetRequest(ctx, a.client, a.baseURL, map[string]string{}, a.headers, &availableLanguages, "available-languages")
+	err := cfg.get(ctx, EndpointAvailableLanguages, map[string]string{}, &availableLanguages, call)
 	if err != nil {
 		return nil, err
 	}
 	return &availableLanguages.AvailableLanguagesResponse, nil
 }
+
+// get makes a GET request to the given endpoint using the configuration
+// of the API controller.
+func (c *config) get(ctx context.Context, endpoint string, queryParams map[string]string, response core.ResponseReader, call callConfig) error {
+	meta := call.meta
+	if meta == nil {
+		meta = &ResponseMeta{}
+	}
+	if call.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, call.timeout)
+		defer cancel()
+	}
+	baseURL, pool := c.baseURL, c.pool
+	if call.baseURL != "" {
+		baseURL, pool = call.baseURL, nil
+	}
+	headers := c.requestHeaders(call)
+	ctx = core.ContextWithEndpoint(ctx, endpoint)
+	err := core.Get(ctx, core.Request{
+		Client:      chain(c.client, c.middleware),
+		BaseURL:     baseURL,
+		Paths:       []string{endpoint},
+		QueryParams: queryParams,
+		Headers:     headers,
+		Retry:       c.retry,
+		Limiters:    c.limiters(endpoint),
+		Meta:        meta,
+		Cache:       c.cacheFor(endpoint),
+		CacheTTL:    c.cacheTTL,
+		Dedup:       c.dedup,
+		Breaker:     c.breaker,
+		Pool:        pool,
+	}, response)
+	c.log.logCall(ctx, endpoint, queryParams, headers, meta, err)
+	return err
+}
+
+// requestHeaders returns the headers of the API with those of the call.
+func (c *config) requestHeaders(call callConfig) map[string]string {
+	if len(call.headers) == 0 {
+		return c.headers
+	}
+	headers := maps.Clone(c.headers)
+	setHeaders(headers, call.headers)
+	return headers
+}
+
+// logUnsent logs a call answered without sending a request, either because
+// it failed validation or was served from the SquareCache, so that every
+// call is logged. It returns err.
+func (c *config) logUnsent(ctx context.Context, endpoint string, queryParams map[string]string, call callConfig, meta *ResponseMeta, err error) error {
+	c.log.logCall(ctx, endpoint, queryParams, c.requestHeaders(call), meta, err)
+	return err
+}
+
+// limiters returns the limiters applying to the given endpoint,
+// the endpoint specific one first.
+func (c *config) limiters(endpoint string) []core.Limiter {
+	var limiters []core.Limiter
+	if limiter, ok := c.endpointLimiters[endpoint]; ok {
+		limiters = append(limiters, limiter)
+	}
+	if c.limiter != nil {
+		limiters = append(limiters, c.limiter)
+	}
+	return limiters
+}
+
+// validate runs the given validation of the request options, unless
+// validation has been disabled with WithoutValidation.
+func (c *config) validate(check func(ve *ValidationError)) error {
+	if c.skipValidation {
+		return nil
+	}
+	var ve ValidationError
+	check(&ve)
+	return ve.Err()
+}
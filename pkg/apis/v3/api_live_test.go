@@ -0,0 +1,401 @@
+//go:build live
+
+// The tests of this file check the responses of the live API, they are run
+// with `go test -tags live ./...` and the X_API_KEY environment variable.
+// API_URL optionally sets the base URL of the API.
+
+package v3_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+var (
+	apiURL = os.Getenv("API_URL")
+)
+
+var c2cGeoJson = `{"features":[{"bbox":[-0.195543,51.520833,-0.195499,51.52086],"geometry":{"coordinates":[-0.195521,51.520847],"type":"Point"},"type":"Feature","properties":{"country":"GB","nearestPlace":"Bayswater, London","words":"filled.count.soap","language":"en","map":"https:\/\/w3w.co\/filled.count.soap"}}],"type":"FeatureCollection"}`
+var c23waJson = `{"country":"GB","square":{"southwest":{"lng":-1.246252,"lat":51.751159},"northeast":{"lng":-1.246208,"lat":51.751186}},"nearestPlace":"Oxford, Oxfordshire","coordinates":{"lng":-1.24623,"lat":51.751172},"words":"pretty.needed.chill","language":"en","map":"https:\/\/w3w.co\/pretty.needed.chill"}`
+var c23waGeoJson = `{"features":[{"bbox":[-1.246252,51.751159,-1.246208,51.751186],"geometry":{"coordinates":[-1.24623,51.751172],"type":"Point"},"type":"Feature","properties":{"country":"GB","nearestPlace":"Oxford, Oxfordshire","words":"pretty.needed.chill","language":"en","map":"https:\/\/w3w.co\/pretty.needed.chill"}}],"type":"FeatureCollection"}`
+
+// setupLiveAPI returns an API calling the live API, or the server at
+// API_URL, with the key in X_API_KEY.
+func setupLiveAPI(t *testing.T) v3.API {
+	apiKey := os.Getenv("X_API_KEY")
+	if apiKey == "" {
+		t.Fatal("ERROR: X_API_KEY is empty or not found")
+	}
+	httpClient := http.DefaultClient
+	svc := v3.NewAPI(apiKey, v3.WithClient(httpClient), v3.WithCustomHeader("x-temp-header", "temp"), v3.WithCustomBaseURL(apiURL))
+	return svc
+}
+
+func TestLiveConvertToCoordinatesJSON(t *testing.T) {
+	resp, err := setupLiveAPI(t).ConvertToCoordinates(context.Background(), "filled.count.soap", nil)
+	if err != nil {
+		t.Fatalf("ERROR: Failed to get cordinates from API due to err : %v", err)
+	}
+	var expected v3.ConvertAPIJsonResponse
+	json.Unmarshal([]byte(c2cJson), &expected)
+	if !reflect.DeepEqual(expected, *resp) {
+		t.Fatalf("ERROR: Expected output '%v' recieved '%v'", expected, *resp)
+	}
+}
+
+func TestLiveConvertToCoordinatesGeoJSON(t *testing.T) {
+	resp, err := setupLiveAPI(t).ConvertToCoordinatesGeoJson(context.Background(), "filled.count.soap", nil)
+	if err != nil {
+		t.Fatalf("ERROR: Failed to get cordinates from API due to err : %v", err)
+	}
+	var expected v3.ConvertAPIGeoJsonResponse
+	json.Unmarshal([]byte(c2cGeoJson), &expected)
+	if !reflect.DeepEqual(expected, *resp) {
+		t.Fatalf("ERROR: Expected output '%v' recieved '%v'", expected, *resp)
+	}
+}
+
+func TestLiveConvertToCoordinatesInvalidWords(t *testing.T) {
+	_, err := setupLiveAPI(t).ConvertToCoordinates(context.Background(), "fill.fake.fill", nil)
+	if err == nil {
+		t.Fatal("ERROR: error should be set to BadWords")
+	}
+}
+
+func TestLiveConvertTo3WAJSON(t *testing.T) {
+	resp, err := setupLiveAPI(t).ConvertTo3wa(context.Background(), core.Coordinates{
+		Lng: -1.24623,
+		Lat: 51.751172,
+	}, nil)
+	if err != nil {
+		t.Fatalf("ERROR: Failed to get cordinates from API due to err : %v", err)
+	}
+
+	var expected v3.ConvertAPIJsonResponse
+	json.Unmarshal([]byte(c23waJson), &expected)
+	if !reflect.DeepEqual(expected, *resp) {
+		t.Fatalf("ERROR: Expected output '%v' recieved '%v'", expected.Words, resp.Words)
+	}
+}
+
+func TestLiveConvertTo3WAGeoJSON(t *testing.T) {
+	resp, err := setupLiveAPI(t).ConvertTo3waGeoJson(context.Background(), core.Coordinates{
+		Lng: -1.24623,
+		Lat: 51.751172,
+	}, nil)
+	if err != nil {
+		t.Fatalf("ERROR: Failed to get cordinates from API due to err : %v", err)
+	}
+	var expected v3.ConvertAPIGeoJsonResponse
+	json.Unmarshal([]byte(c23waGeoJson), &expected)
+	if !reflect.DeepEqual(expected, *resp) {
+		t.Fatalf("ERROR: Expected output '%v' recieved '%v'", expected, *resp)
+	}
+}
+
+func TestLiveConvertTo3WAInvalidWords(t *testing.T) {
+	_, err := setupLiveAPI(t).ConvertToCoordinates(context.Background(), "fill.fake.fill", nil)
+	if err == nil {
+		t.Fatal("ERROR: error should be set to BadWords")
+	}
+	var errResp *v3.ErrorResponse
+	if !errors.As(err, &errResp) {
+		t.Fatal("ERROR: error should be of type ErrorResponse")
+	}
+}
+
+func TestLiveGridSectionJSON(t *testing.T) {
+
+	_, err := setupLiveAPI(t).GridSection(context.Background(), v3.BoundingBox{
+		SouthWest: core.Coordinates{
+			Lat: 52.207988,
+			Lng: 0.116126,
+		},
+		NorthEast: core.Coordinates{
+			Lat: 52.208867,
+			Lng: 0.117540,
+		},
+	})
+	if err != nil {
+		t.Fatalf("ERROR: Failed to get grid section from API - %v", err)
+	}
+	if err != nil {
+		t.Fatalf("ERROR: Failed to get cordinates from API due to err : %v", err)
+	}
+}
+
+func TestLiveGridSectionGeoJSON(t *testing.T) {
+	_, err := setupLiveAPI(t).GridSectionGeoJson(context.Background(), v3.BoundingBox{
+		SouthWest: core.Coordinates{
+			Lat: 52.207988,
+			Lng: 0.116126,
+		},
+		NorthEast: core.Coordinates{
+			Lat: 52.208867,
+			Lng: 0.117540,
+		},
+	})
+	if err != nil {
+		t.Fatalf("ERROR: Failed to get grid section from API - %v", err)
+	}
+	if err != nil {
+		t.Fatalf("ERROR: Failed to get cordinates from API due to err : %v", err)
+	}
+}
+
+func TestLiveAvailableLanguages(t *testing.T) {
+	_, err := setupLiveAPI(t).AvailableLanguages(context.Background())
+	if err != nil {
+		t.Fatalf("ERROR: Error occurred trying to retrieve languages")
+	}
+}
+
+// AutoSuggest
+// input - plan.clips.a
+const autoSuggest = `{"suggestions":[{"country":"US","nearestPlace":"Absecon, New Jersey","words":"plan.clips.also","rank":1,"language":"en"},{"country":"US","nearestPlace":"Sunland, California","words":"plan.clips.back","rank":2,"language":"en"},{"country":"US","nearestPlace":"Keego Harbor, Michigan","words":"plan.clips.each","rank":3,"language":"en"}]}`
+
+// focus - 51.521251,-0.203586
+const autoSuggestFocus = `{"suggestions":[{"country":"GB","nearestPlace":"Brixton Hill, London","words":"plan.clips.area","rank":1,"distanceToFocusKm":11,"language":"en"},{"country":"GB","nearestPlace":"Borehamwood, Hertfordshire","words":"plan.clips.arts","rank":2,"distanceToFocusKm":16,"language":"en"},{"country":"GB","nearestPlace":"Wood Green, London","words":"plan.slips.cage","rank":3,"distanceToFocusKm":13,"language":"en"}]}`
+
+// clip-to-country - NZ,AU
+const autoSuggestClipToCountry = `{"suggestions":[{"country":"AU","nearestPlace":"Emerald, Queensland","words":"plan.clips.bias","rank":1,"language":"en"},{"country":"AU","nearestPlace":"Kumpupintil, Western Australia","words":"plan.clips.atop","rank":2,"language":"en"},{"country":"AU","nearestPlace":"Melville, Western Australia","words":"plan.clips.clad","rank":3,"language":"en"}]}`
+
+// clip-to-bounding-box - 51.521,-0.343,52.6,2.3324
+const autoSuggestClipToBoundingBox = `{"suggestions":[{"country":"GB","nearestPlace":"Borehamwood, Hertfordshire","words":"plan.clips.arts","rank":1,"language":"en"},{"country":"GB","nearestPlace":"Cambridge, Cambridgeshire","words":"plan.clips.boat","rank":2,"language":"en"},{"country":"GB","nearestPlace":"Rayleigh, Essex","words":"plan.clip.deal","rank":3,"language":"en"}]}`
+
+// clip-to-circle - 51.521,-0.343,142
+const autoSuggestClipToCirle = `{"suggestions":[{"country":"GB","nearestPlace":"Brixton Hill, London","words":"plan.clips.area","rank":1,"language":"en"},{"country":"GB","nearestPlace":"Borehamwood, Hertfordshire","words":"plan.clips.arts","rank":2,"language":"en"},{"country":"GB","nearestPlace":"Cambridge, Cambridgeshire","words":"plan.clips.boat","rank":3,"language":"en"}]}`
+
+// clip-to-polygon=51.521,-0.343,52.6,2.3324,54.234,8.343,51.521,-0.343
+const autoSuggestClipToPolygon = `{"suggestions":[{"country":"GB","nearestPlace":"High Ongar, Essex","words":"plan.clip.bags","rank":1,"language":"en"},{"country":"GB","nearestPlace":"Wood Green, London","words":"plan.slips.cage","rank":2,"language":"en"},{"country":"GB","nearestPlace":"High Ongar, Essex","words":"plan.flips.ants","rank":3,"language":"en"}]}`
+
+// prefer-land - false
+const autoSuggestPreferLandFalse = `{"suggestions":[{"country":"US","nearestPlace":"Absecon, New Jersey","words":"plan.clips.also","rank":1,"language":"en"},{"country":"US","nearestPlace":"Sunland, California","words":"plan.clips.back","rank":2,"language":"en"},{"country":"US","nearestPlace":"Keego Harbor, Michigan","words":"plan.clips.each","rank":3,"language":"en"}]}`
+
+// multiple policies
+// clip-to-circle - 51.521,-0.343,142
+// clip-to-polygon - 51.521,-0.343,52.6,2.3324,54.234,8.343,51.521,-0.343
+// clip-to-bounding-box - 51.521,-0.343,52.6,2.3324
+// clip-to-country=GB
+// language=eng
+const autoSuggestMultiplePolicies = `{"suggestions":[{"country":"GB","nearestPlace":"High Ongar, Essex","words":"plan.clip.bags","rank":1,"language":"en"},{"country":"GB","nearestPlace":"Wood Green, London","words":"plan.slips.cage","rank":2,"language":"en"},{"country":"GB","nearestPlace":"High Ongar, Essex","words":"plan.flips.ants","rank":3,"language":"en"}]}`
+
+func TestLiveAutoSuggest(t *testing.T) {
+	svc := setupLiveAPI(t)
+	asTests := []struct {
+		name     string
+		opts     *v3.AutoSuggestOpts
+		expected string
+	}{
+		{
+			"OnlyInputs",
+			nil,
+			autoSuggest,
+		},
+		{
+			"Focus",
+			&v3.AutoSuggestOpts{
+				Focus: &core.Coordinates{
+					Lat: 51.521251,
+					Lng: -0.203586,
+				},
+			},
+			autoSuggestFocus,
+		},
+		{
+			"ClipToCountry",
+			&v3.AutoSuggestOpts{
+				ClipToCountry: []string{"NZ", "AU"},
+			},
+			autoSuggestClipToCountry,
+		},
+		{
+			"ClipToBoundingBox",
+			&v3.AutoSuggestOpts{
+				ClipToBoundingBox: &v3.BoundingBox{
+					core.Coordinates{
+						Lat: 51.521,
+						Lng: -0.343,
+					},
+					core.Coordinates{
+						Lat: 52.6,
+						Lng: 2.3324,
+					},
+				},
+			},
+			autoSuggestClipToBoundingBox,
+		},
+		{
+			"ClipToCircle",
+			&v3.AutoSuggestOpts{
+				ClipToCircle: &v3.Circle{
+					Center: core.Coordinates{
+						Lat: 51.521,
+						Lng: -0.343,
+					},
+					RadiusKm: 142,
+				},
+			},
+			autoSuggestClipToCirle,
+		},
+		{
+			"ClipToPolygon",
+			&v3.AutoSuggestOpts{
+				ClipToPolygon: []core.Coordinates{
+					{
+						Lat: 51.521,
+						Lng: -0.343,
+					},
+					{
+						Lat: 52.6,
+						Lng: 2.3324,
+					},
+					{
+						Lat: 54.234,
+						Lng: 8.343,
+					},
+					{
+						Lat: 51.521,
+						Lng: -0.343,
+					},
+				},
+			},
+			autoSuggestClipToPolygon,
+		},
+		{
+			"PreferLandFalse",
+			&v3.AutoSuggestOpts{
+				PreferLand: core.Bool(false),
+			},
+			autoSuggestPreferLandFalse,
+		},
+		{
+			"MultiplePolicies",
+			&v3.AutoSuggestOpts{
+				ClipToBoundingBox: &v3.BoundingBox{
+					core.Coordinates{
+						Lat: 51.521,
+						Lng: -0.343,
+					},
+					core.Coordinates{
+						Lat: 52.6,
+						Lng: 2.3324,
+					},
+				},
+				ClipToCountry: []string{"GB"},
+				ClipToPolygon: []core.Coordinates{
+					{
+						Lat: 51.521,
+						Lng: -0.343,
+					},
+					{
+						Lat: 52.6,
+						Lng: 2.3324,
+					},
+					{
+						Lat: 54.234,
+						Lng: 8.343,
+					},
+					{
+						Lat: 51.521,
+						Lng: -0.343,
+					},
+				},
+				ClipToCircle: &v3.Circle{
+					Center: core.Coordinates{
+						Lat: 51.521,
+						Lng: -0.343,
+					},
+					RadiusKm: 142,
+				},
+				Language: "en",
+			},
+			autoSuggestMultiplePolicies,
+		},
+	}
+
+	for _, asTest := range asTests {
+		test := asTest
+		t.Run(asTest.name, func(t *testing.T) {
+			t.Parallel()
+			resp, err := svc.AutoSuggest(context.Background(), "plan.clips.a", test.opts)
+			if err != nil {
+				t.Fatalf("ERROR: Failed to get auto suggest from API - %v", err)
+			}
+			var expected v3.AutoSuggestResponse
+			json.Unmarshal([]byte(test.expected), &expected)
+
+			buffer, err := json.Marshal(resp)
+			if err != nil {
+				t.Fatalf("ERROR: Failed to marshal response - %v", err)
+			}
+
+			if !reflect.DeepEqual(expected, *resp) {
+				t.Fatalf("ERROR: Expected output '%s' recieved '%s'", test.expected, string(buffer))
+			}
+		})
+	}
+}
+
+var (
+	expectedCoordinates = core.Coordinates{
+		Lat: 51.520847,
+		Lng: -0.195521,
+	}
+	expectedSquare = v3.Sqaure{
+		SouthWest: core.Coordinates{
+			Lat: 51.520833,
+			Lng: -0.195543,
+		},
+		NorthEast: core.Coordinates{
+			Lng: -0.195499,
+			Lat: 51.52086,
+		},
+	}
+)
+
+func TestLiveAutoSuggestWithCoordinates(t *testing.T) {
+	svc := setupLiveAPI(t)
+	resp, err := svc.AutoSuggestWithCoordinates(context.Background(), "filled.count.soa", &v3.AutoSuggestOpts{
+		NResults:      core.Int(1),
+		ClipToCountry: []string{"GB"},
+	})
+	if err != nil {
+		t.Fatalf("ERROR: Failed to get auto suggest from API - %v", err)
+	}
+	if len(resp.Suggestions) != 1 {
+		t.Fatalf("ERROR: Expected number of suggestions to be exactly 1 but got %d", len(resp.Suggestions))
+	}
+	if !reflect.DeepEqual(resp.Suggestions[0].Coordinates, expectedCoordinates) {
+		t.Fatalf("ERROR: Expected api suggestions Coordinates to be %+v got %+v", expectedCoordinates, resp.Suggestions[0].Coordinates)
+	}
+	if !reflect.DeepEqual(resp.Suggestions[0].Square, expectedSquare) {
+		t.Fatalf("ERROR: Expected api suggestions Square to be %+v got %+v", expectedSquare, resp.Suggestions[0].Square)
+	}
+}
+
+func TestLiveThreadSafety(t *testing.T) {
+	svc := setupLiveAPI(t)
+	var wg sync.WaitGroup
+	count := 100
+	wg.Add(count)
+	for i := 0; i < count; i++ {
+		go func() {
+			defer wg.Done()
+			_, err := svc.ConvertToCoordinates(context.Background(), "filled.count.soap", nil)
+			if err != nil {
+				t.Errorf("ERROR: Failed to get coordinates from API - %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -2,346 +2,278 @@ package v3_test
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"strings"
 	"sync"
 
-	"net/http"
-	"os"
 	"reflect"
 
 	"testing"
 
 	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
 	"github.com/what3words/w3w-go-wrapper/pkg/core"
-)
-
-var (
-	apiURL = os.Getenv("API_URL")
+	"github.com/what3words/w3w-go-wrapper/pkg/w3wtest"
 )
 
 var c2cJson = `{"country":"GB","square":{"southwest":{"lng":-0.195543,"lat":51.520833},"northeast":{"lng":-0.195499,"lat":51.52086}},"nearestPlace":"Bayswater, London","coordinates":{"lng":-0.195521,"lat":51.520847},"words":"filled.count.soap","language":"en","map":"https:\/\/w3w.co\/filled.count.soap"}`
-var c2cGeoJson = `{"features":[{"bbox":[-0.195543,51.520833,-0.195499,51.52086],"geometry":{"coordinates":[-0.195521,51.520847],"type":"Point"},"type":"Feature","properties":{"country":"GB","nearestPlace":"Bayswater, London","words":"filled.count.soap","language":"en","map":"https:\/\/w3w.co\/filled.count.soap"}}],"type":"FeatureCollection"}`
-var c23waJson = `{"country":"GB","square":{"southwest":{"lng":-1.246252,"lat":51.751159},"northeast":{"lng":-1.246208,"lat":51.751186}},"nearestPlace":"Oxford, Oxfordshire","coordinates":{"lng":-1.24623,"lat":51.751172},"words":"pretty.needed.chill","language":"en","map":"https:\/\/w3w.co\/pretty.needed.chill"}`
-var c23waGeoJson = `{"features":[{"bbox":[-1.246252,51.751159,-1.246208,51.751186],"geometry":{"coordinates":[-1.24623,51.751172],"type":"Point"},"type":"Feature","properties":{"country":"GB","nearestPlace":"Oxford, Oxfordshire","words":"pretty.needed.chill","language":"en","map":"https:\/\/w3w.co\/pretty.needed.chill"}}],"type":"FeatureCollection"}`
 
-// var responses map[string]string = map[string]string{
-// 	"/v3/convert-to-coordinates?words=filled.count.soap":                  c2cJson,
-// 	"/v3/convert-to-coordinates?format=geojson&words=filled.count.soap":   c2cGeoJson,
-// 	"/v3/convert-to-coordinates?words=fill.fake.fill":                     "",
-// 	"/v3/convert-to-3wa?coordinates=51.520833%2C-0.195543":                c23waJson,
-// 	"/v3/convert-to-3wa?coordinates=51.520833%2C-0.195543&format=geojson": c23waGeoJson,
-// 	"/v3/available-languages":                                             "",
-// }
-
-// type MockClient struct {
-// }
+// The tests of this file run against the fake API of w3wtest, see
+// api_live_test.go for the tests against the live API.
+var (
+	testCoordinates = core.Coordinates{Lat: 51.520847, Lng: -0.195521}
+	testWords       = w3wtest.Words(testCoordinates)
+)
 
-// func (mc MockClient) Do(req *http.Request) (*http.Response, error) {
-// 	if req.Method != http.MethodGet {
-// 		return nil, errors.New("invalid method. Test error")
-// 	}
-// 	url := fmt.Sprintf("%s?%s", req.URL.Path, req.URL.RawQuery)
-// 	if responseBody, ok := responses[url]; !ok {
-// 		return nil, errors.New("Test setup error")
-// 	} else {
-// 		resp := http.Response{}
-// 		resp.StatusCode = http.StatusOK
-// 		resp.Body = io.NopCloser(strings.NewReader(responseBody))
+func setupAPI(t *testing.T) (v3.API, *w3wtest.Server) {
+	srv := w3wtest.NewServer(w3wtest.WithPlace("GB", "Bayswater, London"))
+	t.Cleanup(srv.Close)
+	svc := v3.NewAPI("test-key", v3.WithCustomHeader("x-temp-header", "temp"), v3.WithCustomBaseURL(srv.URL))
+	return svc, srv
+}
 
-// 		return &resp, nil
-// 	}
-// }
+// expectedConvert returns the response of the fake API to the conversion
+// of the given fake address.
+func expectedConvert(t *testing.T, words string) v3.ConvertAPIJsonResponse {
+	square, ok := w3wtest.Square(words)
+	if !ok {
+		t.Fatalf("ERROR: %s is not a fake what3words", words)
+	}
+	coordinates, _ := w3wtest.Coordinates(words)
+	return v3.ConvertAPIJsonResponse{
+		Country:      "GB",
+		Square:       square,
+		NearestPlace: "Bayswater, London",
+		Coordinates:  coordinates,
+		Words:        words,
+		Language:     "en",
+		MapUrl:       "https://w3w.co/" + words,
+	}
+}
 
-func setupAPI(t *testing.T) v3.API {
-	apiKey := os.Getenv("X_API_KEY")
-	if apiKey == "" {
-		t.Fatal("ERROR: X_API_KEY is empty or not found")
-	}
-	httpClient := http.DefaultClient
-	svc := v3.NewAPI(apiKey, v3.WithClient(httpClient), v3.WithCustomHeader("x-temp-header", "temp"), v3.WithCustomBaseURL(apiURL))
-	return svc
+// checkGeoJson checks the GeoJSON response holds the same conversion as
+// the JSON one.
+func checkGeoJson(t *testing.T, expected v3.ConvertAPIJsonResponse, resp *v3.ConvertAPIGeoJsonResponse) {
+	if len(resp.Features) != 1 || resp.GeoJsonType != "FeatureCollection" {
+		t.Fatalf("ERROR: Expected a FeatureCollection of 1 feature recieved '%+v'", *resp)
+	}
+	feature := resp.Features[0]
+	bbox := []float64{expected.Square.SouthWest.Lng, expected.Square.SouthWest.Lat, expected.Square.NorthEast.Lng, expected.Square.NorthEast.Lat}
+	if !reflect.DeepEqual(feature.Bbox, bbox) {
+		t.Fatalf("ERROR: Expected bbox '%v' recieved '%v'", bbox, feature.Bbox)
+	}
+	if point := []float64{expected.Coordinates.Lng, expected.Coordinates.Lat}; feature.Geometry.GeometryType != "Point" || !reflect.DeepEqual(feature.Geometry.Coordinates, point) {
+		t.Fatalf("ERROR: Expected the point '%v' recieved '%+v'", point, feature.Geometry)
+	}
+	properties := feature.Properties
+	if properties.Country != expected.Country || properties.NearestPlace != expected.NearestPlace || properties.Words != expected.Words ||
+		properties.Language != expected.Language || properties.MapURL != expected.MapUrl {
+		t.Fatalf("ERROR: Expected output '%v' recieved '%+v'", expected, properties)
+	}
 }
 
 func TestConvertToCoordinatesJSON(t *testing.T) {
-	resp, err := setupAPI(t).ConvertToCoordinates(context.Background(), "filled.count.soap", nil)
+	svc, srv := setupAPI(t)
+	resp, err := svc.ConvertToCoordinates(context.Background(), testWords, nil)
 	if err != nil {
 		t.Fatalf("ERROR: Failed to get cordinates from API due to err : %v", err)
 	}
-	var expected v3.ConvertAPIJsonResponse
-	json.Unmarshal([]byte(c2cJson), &expected)
-	if !reflect.DeepEqual(expected, *resp) {
+	if expected := expectedConvert(t, testWords); !reflect.DeepEqual(expected, *resp) {
 		t.Fatalf("ERROR: Expected output '%v' recieved '%v'", expected, *resp)
 	}
+	requests := srv.RequestsTo(v3.EndpointConvertToCoordinates)
+	if len(requests) != 1 || requests[0].Header.Get("x-temp-header") != "temp" {
+		t.Fatalf("ERROR: Expected 1 request with the custom header, got %+v", requests)
+	}
 }
 
 func TestConvertToCoordinatesGeoJSON(t *testing.T) {
-	resp, err := setupAPI(t).ConvertToCoordinatesGeoJson(context.Background(), "filled.count.soap", nil)
+	svc, _ := setupAPI(t)
+	resp, err := svc.ConvertToCoordinatesGeoJson(context.Background(), testWords, nil)
 	if err != nil {
 		t.Fatalf("ERROR: Failed to get cordinates from API due to err : %v", err)
 	}
-	var expected v3.ConvertAPIGeoJsonResponse
-	json.Unmarshal([]byte(c2cGeoJson), &expected)
-	if !reflect.DeepEqual(expected, *resp) {
-		t.Fatalf("ERROR: Expected output '%v' recieved '%v'", expected, *resp)
-	}
+	checkGeoJson(t, expectedConvert(t, testWords), resp)
 }
 
 func TestConvertToCoordinatesInvalidWords(t *testing.T) {
-	_, err := setupAPI(t).ConvertToCoordinates(context.Background(), "fill.fake.fill", nil)
-	if err == nil {
-		t.Fatal("ERROR: error should be set to BadWords")
+	svc, _ := setupAPI(t)
+	_, err := svc.ConvertToCoordinates(context.Background(), "fill.fake.fill", nil)
+	if !errors.Is(err, v3.ErrBadWords) {
+		t.Fatalf("ERROR: error should be set to BadWords, got %v", err)
 	}
 }
 
 func TestConvertTo3WAJSON(t *testing.T) {
-	resp, err := setupAPI(t).ConvertTo3wa(context.Background(), core.Coordinates{
-		Lng: -1.24623,
-		Lat: 51.751172,
-	}, nil)
+	svc, _ := setupAPI(t)
+	resp, err := svc.ConvertTo3wa(context.Background(), testCoordinates, nil)
 	if err != nil {
 		t.Fatalf("ERROR: Failed to get cordinates from API due to err : %v", err)
 	}
-
-	var expected v3.ConvertAPIJsonResponse
-	json.Unmarshal([]byte(c23waJson), &expected)
-	if !reflect.DeepEqual(expected, *resp) {
-		t.Fatalf("ERROR: Expected output '%v' recieved '%v'", expected.Words, resp.Words)
+	if expected := expectedConvert(t, testWords); !reflect.DeepEqual(expected, *resp) {
+		t.Fatalf("ERROR: Expected output '%v' recieved '%v'", expected, *resp)
 	}
 }
 
 func TestConvertTo3WAGeoJSON(t *testing.T) {
-	resp, err := setupAPI(t).ConvertTo3waGeoJson(context.Background(), core.Coordinates{
-		Lng: -1.24623,
-		Lat: 51.751172,
-	}, nil)
+	svc, _ := setupAPI(t)
+	resp, err := svc.ConvertTo3waGeoJson(context.Background(), testCoordinates, nil)
 	if err != nil {
 		t.Fatalf("ERROR: Failed to get cordinates from API due to err : %v", err)
 	}
-	var expected v3.ConvertAPIGeoJsonResponse
-	json.Unmarshal([]byte(c23waGeoJson), &expected)
-	if !reflect.DeepEqual(expected, *resp) {
-		t.Fatalf("ERROR: Expected output '%v' recieved '%v'", expected, *resp)
-	}
+	checkGeoJson(t, expectedConvert(t, testWords), resp)
 }
 
 func TestConvertTo3WAInvalidWords(t *testing.T) {
-	_, err := setupAPI(t).ConvertToCoordinates(context.Background(), "fill.fake.fill", nil)
+	svc, _ := setupAPI(t)
+	_, err := svc.ConvertToCoordinates(context.Background(), "filled.count.soap", nil)
 	if err == nil {
 		t.Fatal("ERROR: error should be set to BadWords")
 	}
-	_, ok := err.(*v3.ErrorResponse)
-	if !ok {
+	var errResp *v3.ErrorResponse
+	if !errors.As(err, &errResp) {
 		t.Fatal("ERROR: error should be of type ErrorResponse")
 	}
+	if errResp.Code != v3.ErrorCodeBadWords {
+		t.Fatalf("ERROR: Expected code %s got %s", v3.ErrorCodeBadWords, errResp.Code)
+	}
 }
 
-func TestGridSectionJSON(t *testing.T) {
+var gridSectionBox = v3.BoundingBox{
+	SouthWest: core.Coordinates{
+		Lat: 52.207988,
+		Lng: 0.116126,
+	},
+	NorthEast: core.Coordinates{
+		Lat: 52.208867,
+		Lng: 0.117540,
+	},
+}
 
-	_, err := setupAPI(t).GridSection(context.Background(), v3.BoundingBox{
-		SouthWest: core.Coordinates{
-			Lat: 52.207988,
-			Lng: 0.116126,
-		},
-		NorthEast: core.Coordinates{
-			Lat: 52.208867,
-			Lng: 0.117540,
-		},
-	})
+func TestGridSectionJSON(t *testing.T) {
+	svc, _ := setupAPI(t)
+	resp, err := svc.GridSection(context.Background(), gridSectionBox)
 	if err != nil {
 		t.Fatalf("ERROR: Failed to get grid section from API - %v", err)
 	}
-	if err != nil {
-		t.Fatalf("ERROR: Failed to get cordinates from API due to err : %v", err)
+	if len(resp.Lines) == 0 {
+		t.Fatal("ERROR: Expected the lines of the grid section")
+	}
+	for _, line := range resp.Lines {
+		if line.Start.Lat != line.End.Lat && line.Start.Lng != line.End.Lng {
+			t.Fatalf("ERROR: Expected horizontal or vertical lines, got %+v", line)
+		}
 	}
 }
 
 func TestGridSectionGeoJSON(t *testing.T) {
-	_, err := setupAPI(t).GridSectionGeoJson(context.Background(), v3.BoundingBox{
-		SouthWest: core.Coordinates{
-			Lat: 52.207988,
-			Lng: 0.116126,
-		},
-		NorthEast: core.Coordinates{
-			Lat: 52.208867,
-			Lng: 0.117540,
-		},
-	})
+	svc, _ := setupAPI(t)
+	resp, err := svc.GridSectionGeoJson(context.Background(), gridSectionBox)
 	if err != nil {
 		t.Fatalf("ERROR: Failed to get grid section from API - %v", err)
 	}
-	if err != nil {
-		t.Fatalf("ERROR: Failed to get cordinates from API due to err : %v", err)
+	if len(resp.Features) != 1 || resp.Features[0].Geometry.GeometryType != "MultiLineString" || len(resp.Features[0].Geometry.Coordinates) == 0 {
+		t.Fatalf("ERROR: Expected a MultiLineString of the grid section, got %+v", *resp)
 	}
 }
 
 func TestAvailableLanguages(t *testing.T) {
-	_, err := setupAPI(t).AvailableLanguages(context.Background())
+	svc, _ := setupAPI(t)
+	resp, err := svc.AvailableLanguages(context.Background())
 	if err != nil {
 		t.Fatalf("ERROR: Error occurred trying to retrieve languages")
 	}
+	if len(resp.Languages) == 0 || resp.Languages[0].Code != "en" {
+		t.Fatalf("ERROR: Expected the languages of the fake API, got %+v", resp.Languages)
+	}
 }
 
-// AutoSuggest
-// input - plan.clips.a
-const autoSuggest = `{"suggestions":[{"country":"US","nearestPlace":"Absecon, New Jersey","words":"plan.clips.also","rank":1,"language":"en"},{"country":"US","nearestPlace":"Sunland, California","words":"plan.clips.back","rank":2,"language":"en"},{"country":"US","nearestPlace":"Keego Harbor, Michigan","words":"plan.clips.each","rank":3,"language":"en"}]}`
-
-// focus - 51.521251,-0.203586
-const autoSuggestFocus = `{"suggestions":[{"country":"GB","nearestPlace":"Brixton Hill, London","words":"plan.clips.area","rank":1,"distanceToFocusKm":11,"language":"en"},{"country":"GB","nearestPlace":"Borehamwood, Hertfordshire","words":"plan.clips.arts","rank":2,"distanceToFocusKm":16,"language":"en"},{"country":"GB","nearestPlace":"Wood Green, London","words":"plan.slips.cage","rank":3,"distanceToFocusKm":13,"language":"en"}]}`
-
-// clip-to-country - NZ,AU
-const autoSuggestClipToCountry = `{"suggestions":[{"country":"AU","nearestPlace":"Emerald, Queensland","words":"plan.clips.bias","rank":1,"language":"en"},{"country":"AU","nearestPlace":"Kumpupintil, Western Australia","words":"plan.clips.atop","rank":2,"language":"en"},{"country":"AU","nearestPlace":"Melville, Western Australia","words":"plan.clips.clad","rank":3,"language":"en"}]}`
-
-// clip-to-bounding-box - 51.521,-0.343,52.6,2.3324
-const autoSuggestClipToBoundingBox = `{"suggestions":[{"country":"GB","nearestPlace":"Borehamwood, Hertfordshire","words":"plan.clips.arts","rank":1,"language":"en"},{"country":"GB","nearestPlace":"Cambridge, Cambridgeshire","words":"plan.clips.boat","rank":2,"language":"en"},{"country":"GB","nearestPlace":"Rayleigh, Essex","words":"plan.clip.deal","rank":3,"language":"en"}]}`
-
-// clip-to-circle - 51.521,-0.343,142
-const autoSuggestClipToCirle = `{"suggestions":[{"country":"GB","nearestPlace":"Brixton Hill, London","words":"plan.clips.area","rank":1,"language":"en"},{"country":"GB","nearestPlace":"Borehamwood, Hertfordshire","words":"plan.clips.arts","rank":2,"language":"en"},{"country":"GB","nearestPlace":"Cambridge, Cambridgeshire","words":"plan.clips.boat","rank":3,"language":"en"}]}`
-
-// clip-to-polygon=51.521,-0.343,52.6,2.3324,54.234,8.343,51.521,-0.343
-const autoSuggestClipToPolygon = `{"suggestions":[{"country":"GB","nearestPlace":"High Ongar, Essex","words":"plan.clip.bags","rank":1,"language":"en"},{"country":"GB","nearestPlace":"Wood Green, London","words":"plan.slips.cage","rank":2,"language":"en"},{"country":"GB","nearestPlace":"High Ongar, Essex","words":"plan.flips.ants","rank":3,"language":"en"}]}`
-
-// prefer-land - false
-const autoSuggestPreferLandFalse = `{"suggestions":[{"country":"US","nearestPlace":"Absecon, New Jersey","words":"plan.clips.also","rank":1,"language":"en"},{"country":"US","nearestPlace":"Sunland, California","words":"plan.clips.back","rank":2,"language":"en"},{"country":"US","nearestPlace":"Keego Harbor, Michigan","words":"plan.clips.each","rank":3,"language":"en"}]}`
-
-// multiple policies
-// clip-to-circle - 51.521,-0.343,142
-// clip-to-polygon - 51.521,-0.343,52.6,2.3324,54.234,8.343,51.521,-0.343
-// clip-to-bounding-box - 51.521,-0.343,52.6,2.3324
-// clip-to-country=GB
-// language=eng
-const autoSuggestMultiplePolicies = `{"suggestions":[{"country":"GB","nearestPlace":"High Ongar, Essex","words":"plan.clip.bags","rank":1,"language":"en"},{"country":"GB","nearestPlace":"Wood Green, London","words":"plan.slips.cage","rank":2,"language":"en"},{"country":"GB","nearestPlace":"High Ongar, Essex","words":"plan.flips.ants","rank":3,"language":"en"}]}`
-
 func TestAutoSuggest(t *testing.T) {
-	svc := setupAPI(t)
+	svc, srv := setupAPI(t)
+	// The fake suggests the existing addresses starting with the input, only
+	// keeping the first letter of the last word leaves enough of them.
+	input := testWords[:len(testWords)-4]
+	focus := core.Coordinates{Lat: 51.521251, Lng: -0.203586}
+	// The suggestions of the fake are spread around the world, the clipping
+	// areas are large enough to hold some of them.
+	boundingBox := v3.BoundingBox{
+		SouthWest: core.Coordinates{Lat: 0, Lng: -180},
+		NorthEast: core.Coordinates{Lat: 90, Lng: 0},
+	}
+	polygon := []core.Coordinates{
+		{Lat: 0, Lng: -180},
+		{Lat: 90, Lng: -180},
+		{Lat: 90, Lng: 0},
+		{Lat: 0, Lng: 0},
+		{Lat: 0, Lng: -180},
+	}
+	circle := v3.Circle{Center: focus, RadiusKm: 8000}
+	inBoundingBox := func(c core.Coordinates) bool {
+		return c.Lat >= 0 && c.Lat <= 90 && c.Lng >= -180 && c.Lng <= 0
+	}
+
 	asTests := []struct {
-		name     string
-		opts     *v3.AutoSuggestOpts
-		expected string
+		name  string
+		opts  *v3.AutoSuggestOpts
+		query map[string]string
+		// n is the expected number of suggestions.
+		n int
+		// check checks the coordinates of a suggestion, if set.
+		check func(core.Coordinates) bool
 	}{
 		{
-			"OnlyInputs",
-			nil,
-			autoSuggest,
+			name: "OnlyInputs",
+			n:    3,
 		},
 		{
-			"Focus",
-			&v3.AutoSuggestOpts{
-				Focus: &core.Coordinates{
-					Lat: 51.521251,
-					Lng: -0.203586,
-				},
-			},
-			autoSuggestFocus,
+			name:  "Focus",
+			opts:  &v3.AutoSuggestOpts{Focus: &focus},
+			query: map[string]string{"focus": "51.521251,-0.203586"},
+			n:     3,
 		},
 		{
-			"ClipToCountry",
-			&v3.AutoSuggestOpts{
-				ClipToCountry: []string{"NZ", "AU"},
-			},
-			autoSuggestClipToCountry,
+			name:  "ClipToCountry",
+			opts:  &v3.AutoSuggestOpts{ClipToCountry: []string{"NZ", "AU"}},
+			query: map[string]string{"clip-to-country": "NZ,AU"},
 		},
 		{
-			"ClipToBoundingBox",
-			&v3.AutoSuggestOpts{
-				ClipToBoundingBox: &v3.BoundingBox{
-					core.Coordinates{
-						Lat: 51.521,
-						Lng: -0.343,
-					},
-					core.Coordinates{
-						Lat: 52.6,
-						Lng: 2.3324,
-					},
-				},
-			},
-			autoSuggestClipToBoundingBox,
+			name:  "ClipToBoundingBox",
+			opts:  &v3.AutoSuggestOpts{ClipToBoundingBox: &boundingBox},
+			query: map[string]string{"clip-to-bounding-box": "0.000000,-180.000000,90.000000,0.000000"},
+			n:     3,
+			check: inBoundingBox,
 		},
 		{
-			"ClipToCircle",
-			&v3.AutoSuggestOpts{
-				ClipToCircle: &v3.Circle{
-					Center: core.Coordinates{
-						Lat: 51.521,
-						Lng: -0.343,
-					},
-					RadiusKm: 142,
-				},
-			},
-			autoSuggestClipToCirle,
+			name:  "ClipToCircle",
+			opts:  &v3.AutoSuggestOpts{ClipToCircle: &circle, Focus: &focus},
+			query: map[string]string{"clip-to-circle": "51.521251,-0.203586,8000.000000"},
+			n:     3,
 		},
 		{
-			"ClipToPolygon",
-			&v3.AutoSuggestOpts{
-				ClipToPolygon: []core.Coordinates{
-					{
-						Lat: 51.521,
-						Lng: -0.343,
-					},
-					{
-						Lat: 52.6,
-						Lng: 2.3324,
-					},
-					{
-						Lat: 54.234,
-						Lng: 8.343,
-					},
-					{
-						Lat: 51.521,
-						Lng: -0.343,
-					},
-				},
-			},
-			autoSuggestClipToPolygon,
+			name:  "ClipToPolygon",
+			opts:  &v3.AutoSuggestOpts{ClipToPolygon: polygon},
+			query: map[string]string{"clip-to-polygon": "0.000000,-180.000000,90.000000,-180.000000,90.000000,0.000000,0.000000,0.000000,0.000000,-180.000000"},
+			n:     3,
+			check: inBoundingBox,
 		},
 		{
-			"PreferLandFalse",
-			&v3.AutoSuggestOpts{
-				PreferLand: core.Bool(false),
-			},
-			autoSuggestPreferLandFalse,
+			name:  "PreferLandFalse",
+			opts:  &v3.AutoSuggestOpts{PreferLand: core.Bool(false)},
+			query: map[string]string{"prefer-land": "false"},
+			n:     3,
 		},
 		{
-			"MultiplePolicies",
-			&v3.AutoSuggestOpts{
-				ClipToBoundingBox: &v3.BoundingBox{
-					core.Coordinates{
-						Lat: 51.521,
-						Lng: -0.343,
-					},
-					core.Coordinates{
-						Lat: 52.6,
-						Lng: 2.3324,
-					},
-				},
-				ClipToCountry: []string{"GB"},
-				ClipToPolygon: []core.Coordinates{
-					{
-						Lat: 51.521,
-						Lng: -0.343,
-					},
-					{
-						Lat: 52.6,
-						Lng: 2.3324,
-					},
-					{
-						Lat: 54.234,
-						Lng: 8.343,
-					},
-					{
-						Lat: 51.521,
-						Lng: -0.343,
-					},
-				},
-				ClipToCircle: &v3.Circle{
-					Center: core.Coordinates{
-						Lat: 51.521,
-						Lng: -0.343,
-					},
-					RadiusKm: 142,
-				},
-				Language: "en",
+			name: "MultiplePolicies",
+			opts: &v3.AutoSuggestOpts{
+				ClipToBoundingBox: &boundingBox,
+				ClipToCountry:     []string{"GB"},
+				ClipToPolygon:     polygon,
+				ClipToCircle:      &circle,
+				Focus:             &focus,
+				Language:          "en",
 			},
-			autoSuggestMultiplePolicies,
+			query: map[string]string{"clip-to-country": "GB", "language": "en"},
+			n:     3,
+			check: inBoundingBox,
 		},
 	}
 
@@ -349,47 +281,52 @@ func TestAutoSuggest(t *testing.T) {
 		test := asTest
 		t.Run(asTest.name, func(t *testing.T) {
 			t.Parallel()
-			resp, err := svc.AutoSuggest(context.Background(), "plan.clips.a", test.opts)
+			resp, err := svc.AutoSuggest(context.Background(), input, test.opts)
 			if err != nil {
 				t.Fatalf("ERROR: Failed to get auto suggest from API - %v", err)
 			}
-			var expected v3.AutoSuggestResponse
-			json.Unmarshal([]byte(test.expected), &expected)
-
-			buffer, err := json.Marshal(resp)
-			if err != nil {
-				t.Fatalf("ERROR: Failed to marshal response - %v", err)
+			if len(resp.Suggestions) != test.n {
+				t.Fatalf("ERROR: Expected %d suggestions recieved '%+v'", test.n, resp.Suggestions)
 			}
-
-			if !reflect.DeepEqual(expected, *resp) {
-				t.Fatalf("ERROR: Expected output '%s' recieved '%s'", test.expected, string(buffer))
+			for i, suggestion := range resp.Suggestions {
+				if !strings.HasPrefix(suggestion.Words, input) || suggestion.Rank != i+1 || suggestion.Country != "GB" || suggestion.Language != "en" {
+					t.Fatalf("ERROR: Unexpected suggestion %d for %s: '%+v'", i, input, suggestion)
+				}
+				if test.opts != nil && test.opts.Focus != nil {
+					if i > 0 && suggestion.DistanceToFocusKm < resp.Suggestions[i-1].DistanceToFocusKm {
+						t.Fatalf("ERROR: Expected the suggestions to be sorted by distance, got '%+v'", resp.Suggestions)
+					}
+					if test.opts.ClipToCircle != nil && float64(suggestion.DistanceToFocusKm) > test.opts.ClipToCircle.RadiusKm {
+						t.Fatalf("ERROR: Expected %s to be within the circle, got %dkm", suggestion.Words, suggestion.DistanceToFocusKm)
+					}
+				}
+				if coordinates, _ := w3wtest.Coordinates(suggestion.Words); test.check != nil && !test.check(coordinates) {
+					t.Fatalf("ERROR: Expected %s at %+v to be within the clipping area", suggestion.Words, coordinates)
+				}
+			}
+			for _, request := range srv.RequestsTo(v3.EndpointAutoSuggest) {
+				if request.Query.Get("input") != input {
+					continue
+				}
+				matches := true
+				for name, value := range test.query {
+					matches = matches && request.Query.Get(name) == value
+				}
+				if matches {
+					return
+				}
 			}
+			t.Fatalf("ERROR: Expected a request with the query %v", test.query)
 		})
 	}
 }
 
-var (
-	expectedCoordinates = core.Coordinates{
-		Lat: 51.520847,
-		Lng: -0.195521,
-	}
-	expectedSquare = v3.Sqaure{
-		SouthWest: core.Coordinates{
-			Lat: 51.520833,
-			Lng: -0.195543,
-		},
-		NorthEast: core.Coordinates{
-			Lng: -0.195499,
-			Lat: 51.52086,
-		},
-	}
-)
-
 func TestAutoSuggestWithCoordinates(t *testing.T) {
-	svc := setupAPI(t)
-	resp, err := svc.AutoSuggestWithCoordinates(context.Background(), "filled.count.soa", &v3.AutoSuggestOpts{
+	svc, _ := setupAPI(t)
+	resp, err := svc.AutoSuggestWithCoordinates(context.Background(), testWords[:len(testWords)-1], &v3.AutoSuggestOpts{
 		NResults:      core.Int(1),
 		ClipToCountry: []string{"GB"},
+		Focus:         &testCoordinates,
 	})
 	if err != nil {
 		t.Fatalf("ERROR: Failed to get auto suggest from API - %v", err)
@@ -397,28 +334,35 @@ func TestAutoSuggestWithCoordinates(t *testing.T) {
 	if len(resp.Suggestions) != 1 {
 		t.Fatalf("ERROR: Expected number of suggestions to be exactly 1 but got %d", len(resp.Suggestions))
 	}
-	if !reflect.DeepEqual(resp.Suggestions[0].Coordinates, expectedCoordinates) {
-		t.Fatalf("ERROR: Expected api suggestions Coordinates to be %+v got %+v", expectedCoordinates, resp.Suggestions[0].Coordinates)
+	expected := expectedConvert(t, testWords)
+	if resp.Suggestions[0].Words != testWords {
+		t.Fatalf("ERROR: Expected the suggestion closest to the focus to be %s got %s", testWords, resp.Suggestions[0].Words)
 	}
-	if !reflect.DeepEqual(resp.Suggestions[0].Square, expectedSquare) {
-		t.Fatalf("ERROR: Expected api suggestions Square to be %+v got %+v", expectedSquare, resp.Suggestions[0].Square)
+	if !reflect.DeepEqual(resp.Suggestions[0].Coordinates, expected.Coordinates) {
+		t.Fatalf("ERROR: Expected api suggestions Coordinates to be %+v got %+v", expected.Coordinates, resp.Suggestions[0].Coordinates)
+	}
+	if !reflect.DeepEqual(resp.Suggestions[0].Square, expected.Square) {
+		t.Fatalf("ERROR: Expected api suggestions Square to be %+v got %+v", expected.Square, resp.Suggestions[0].Square)
 	}
 }
 
 // Test thread safety
 func TestThreadSafety(t *testing.T) {
-	svc := setupAPI(t)
+	svc, srv := setupAPI(t)
 	var wg sync.WaitGroup
 	count := 100
 	wg.Add(count)
 	for i := 0; i < count; i++ {
 		go func() {
 			defer wg.Done()
-			_, err := svc.ConvertToCoordinates(context.Background(), "filled.count.soap", nil)
+			_, err := svc.ConvertToCoordinates(context.Background(), testWords, nil)
 			if err != nil {
 				t.Errorf("ERROR: Failed to get coordinates from API - %v", err)
 			}
 		}()
 	}
 	wg.Wait()
+	if n := len(srv.RequestsTo(v3.EndpointConvertToCoordinates)); n != count {
+		t.Fatalf("ERROR: Expected %d requests got %d", count, n)
+	}
 }
@@ -0,0 +1,260 @@
+package v3
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+// ErrBatchAborted is the error of the items of a batch which were not
+// converted because the batch was aborted after a fatal error.
+var ErrBatchAborted = errors.New("w3w: batch aborted")
+
+// BatchResult is the outcome of converting a single item of a batch.
+type BatchResult[In any] struct {
+	// Index of the item in the input, starting at 0.
+	Index int
+	// Input item which was converted.
+	Input In
+	// Response of the API, nil when Err is set.
+	Response *ConvertAPIJsonResponse
+	// Err is the error returned for the item, if any.
+	Err error
+}
+
+// BatchOption configures a batch conversion.
+type BatchOption func(*batchConfig)
+
+type batchConfig struct {
+	concurrency int
+	progress    func(done, total int)
+	isFatal     func(error) bool
+	callOpts    []CallOption
+}
+
+func newBatchConfig(opts []BatchOption) batchConfig {
+	cfg := batchConfig{
+		concurrency: 8,
+		isFatal: func(err error) bool {
+			return IsAuthError(err) || IsQuotaError(err)
+		},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithBatchConcurrency sets the maximum number of calls made concurrently
+// by the batch. Defaults to 8.
+func WithBatchConcurrency(n int) BatchOption {
+	return func(cfg *batchConfig) {
+		cfg.concurrency = max(n, 1)
+	}
+}
+
+// WithBatchProgress calls progress after each item of the batch is converted,
+// in input order, with the number of items done so far and the total number
+// of items, or -1 when converting items from a channel.
+func WithBatchProgress(progress func(done, total int)) BatchOption {
+	return func(cfg *batchConfig) {
+		cfg.progress = progress
+	}
+}
+
+// WithBatchAbortOn sets the function deciding whether an error is fatal,
+// aborting the whole batch. By default the batch is aborted on authentication
+// errors, such as InvalidKey, and when the quota is exceeded.
+func WithBatchAbortOn(isFatal func(error) bool) BatchOption {
+	return func(cfg *batchConfig) {
+		cfg.isFatal = isFatal
+	}
+}
+
+// WithBatchCallOptions passes the given CallOptions to every call of the batch.
+// As calls are made concurrently, WithResponseMetadata must not be used.
+func WithBatchCallOptions(callOpts ...CallOption) BatchOption {
+	return func(cfg *batchConfig) {
+		cfg.callOpts = append(cfg.callOpts, callOpts...)
+	}
+}
+
+// ConvertTo3waBatch converts every coordinates to a three word address using
+// the ConvertTo3wa endpoint, making up to WithBatchConcurrency calls at once.
+// A result is returned for every coordinates, in input order, holding either
+// the response or the error of its call.
+//
+// The batch is aborted on the first fatal error, see WithBatchAbortOn, which
+// is returned alongside the results. Items which were not converted then
+// have the ErrBatchAborted error. If ctx is done before the batch completes,
+// the remaining items have the context error, which is also returned.
+//
+// Example usage:
+//
+//	results, err := ConvertTo3waBatch(ctx, api, coordinates, nil,
+//	    WithBatchConcurrency(16),
+//	    WithBatchProgress(func(done, total int) { log.Printf("%d/%d", done, total) }),
+//	)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, result := range results {
+//	    if result.Err != nil {
+//	        log.Printf("row %d: %v", result.Index, result.Err)
+//	        continue
+//	    }
+//	    fmt.Println(result.Response.Words)
+//	}
+func ConvertTo3waBatch(ctx context.Context, api API, coordinates []core.Coordinates, opts *ConvertAPIOpts, batchOpts ...BatchOption) ([]BatchResult[core.Coordinates], error) {
+	return collectBatch(ctx, coordinates, newBatchConfig(batchOpts), convertTo3waCall(api, opts))
+}
+
+// ConvertToCoordinatesBatch converts every three word address to coordinates
+// using the ConvertToCoordinates endpoint. It behaves as ConvertTo3waBatch.
+func ConvertToCoordinatesBatch(ctx context.Context, api API, words []string, opts *ConvertAPIOpts, batchOpts ...BatchOption) ([]BatchResult[string], error) {
+	return collectBatch(ctx, words, newBatchConfig(batchOpts), convertToCoordinatesCall(api, opts))
+}
+
+// ConvertTo3waStream converts coordinates received from in until it is closed,
+// making up to WithBatchConcurrency calls at once. Results are sent on the
+// returned channel in input order, which is closed once every coordinates
+// has been converted, ctx is done or the batch was aborted by a fatal error.
+// In the latter case the last results hold the fatal error, or ErrBatchAborted
+// for calls which were in flight. The returned channel must be drained.
+func ConvertTo3waStream(ctx context.Context, api API, in <-chan core.Coordinates, opts *ConvertAPIOpts, batchOpts ...BatchOption) <-chan BatchResult[core.Coordinates] {
+	out, _ := runBatch(ctx, in, -1, newBatchConfig(batchOpts), convertTo3waCall(api, opts))
+	return out
+}
+
+// ConvertToCoordinatesStream converts three word addresses received from in
+// to coordinates. It behaves as ConvertTo3waStream.
+func ConvertToCoordinatesStream(ctx context.Context, api API, in <-chan string, opts *ConvertAPIOpts, batchOpts ...BatchOption) <-chan BatchResult[string] {
+	out, _ := runBatch(ctx, in, -1, newBatchConfig(batchOpts), convertToCoordinatesCall(api, opts))
+	return out
+}
+
+type batchCall[In any] func(ctx context.Context, input In, callOpts []CallOption) (*ConvertAPIJsonResponse, error)
+
+func convertTo3waCall(api API, opts *ConvertAPIOpts) batchCall[core.Coordinates] {
+	return func(ctx context.Context, coordinates core.Coordinates, callOpts []CallOption) (*ConvertAPIJsonResponse, error) {
+		return api.ConvertTo3wa(ctx, coordinates, opts, callOpts...)
+	}
+}
+
+func convertToCoordinatesCall(api API, opts *ConvertAPIOpts) batchCall[string] {
+	return func(ctx context.Context, words string, callOpts []CallOption) (*ConvertAPIJsonResponse, error) {
+		return api.ConvertToCoordinates(ctx, words, opts, callOpts...)
+	}
+}
+
+// collectBatch converts all inputs and returns their results in input order.
+func collectBatch[In any](ctx context.Context, inputs []In, cfg batchConfig, call batchCall[In]) ([]BatchResult[In], error) {
+	in := make(chan In, len(inputs))
+	for _, input := range inputs {
+		in <- input
+	}
+	close(in)
+
+	results := make([]BatchResult[In], len(inputs))
+	for i, input := range inputs {
+		results[i] = BatchResult[In]{Index: i, Input: input, Err: ErrBatchAborted}
+	}
+	out, fatal := runBatch(ctx, in, len(inputs), cfg, call)
+	done := 0
+	for result := range out {
+		results[result.Index] = result
+		done++
+	}
+	if err := fatal(); err != nil {
+		return results, err
+	}
+	if done < len(results) {
+		for i := done; i < len(results); i++ {
+			results[i].Err = ctx.Err()
+		}
+		return results, ctx.Err()
+	}
+	return results, nil
+}
+
+// runBatch converts inputs received from in with up to cfg.concurrency calls
+// in flight, sending results in input order. The returned function reports
+// the fatal error which aborted the batch, if any, once the results channel
+// is closed.
+func runBatch[In any](ctx context.Context, in <-chan In, total int, cfg batchConfig, call batchCall[In]) (<-chan BatchResult[In], func() error) {
+	ctx, cancel := context.WithCancel(ctx)
+	var (
+		abortOnce sync.Once
+		aborted   atomic.Bool
+		fatal     error
+	)
+	abort := func(err error) {
+		abortOnce.Do(func() {
+			fatal = err
+			aborted.Store(true)
+			cancel()
+		})
+	}
+
+	// order holds the channel of each item in input order, so that
+	// results are collected in order while calls complete in any order.
+	order := make(chan chan BatchResult[In], cfg.concurrency)
+	sem := make(chan struct{}, cfg.concurrency)
+	go func() {
+		defer close(order)
+		for index := 0; ; index++ {
+			var input In
+			select {
+			case <-ctx.Done():
+				return
+			case next, ok := <-in:
+				if !ok {
+					return
+				}
+				input = next
+			}
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			result := make(chan BatchResult[In], 1)
+			select {
+			case order <- result:
+			case <-ctx.Done():
+				<-sem
+				return
+			}
+			go func(index int, input In) {
+				defer func() { <-sem }()
+				resp, err := call(ctx, input, cfg.callOpts)
+				if err != nil && cfg.isFatal != nil && cfg.isFatal(err) {
+					abort(err)
+				}
+				result <- BatchResult[In]{Index: index, Input: input, Response: resp, Err: err}
+			}(index, input)
+		}
+	}()
+
+	out := make(chan BatchResult[In])
+	go func() {
+		defer close(out)
+		defer cancel()
+		done := 0
+		for next := range order {
+			result := <-next
+			if aborted.Load() && result.Err != fatal && errors.Is(result.Err, context.Canceled) {
+				result.Err = ErrBatchAborted
+			}
+			done++
+			if cfg.progress != nil {
+				cfg.progress(done, total)
+			}
+			out <- result
+		}
+	}()
+	return out, func() error { return fatal }
+}
@@ -0,0 +1,157 @@
+package v3_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+// batchServer answers convert-to-coordinates with the requested words after
+// a random delay, failing words starting with "bad" with BadWords and words
+// starting with "revoked" with InvalidKey.
+func batchServer(t *testing.T, inFlight, maxInFlight, calls *atomic.Int32) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Duration(rand.Intn(5)) * time.Millisecond)
+		words := r.URL.Query().Get("words")
+		switch {
+		case strings.HasPrefix(words, "bad"):
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":"BadWords","message":"Invalid words"}}`))
+		case strings.HasPrefix(words, "revoked"):
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"code":"InvalidKey","message":"Authentication failed; invalid API key"}}`))
+		default:
+			w.Write([]byte(`{"words":"` + words + `"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestConvertToCoordinatesBatch(t *testing.T) {
+	var inFlight, maxInFlight, calls atomic.Int32
+	srv := batchServer(t, &inFlight, &maxInFlight, &calls)
+	api := v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL))
+
+	words := make([]string, 100)
+	for i := range words {
+		words[i] = fmt.Sprintf("word.number.w%d", i)
+	}
+	words[42] = "bad.words.here"
+	var progress []int
+	results, err := v3.ConvertToCoordinatesBatch(context.Background(), api, words, nil,
+		v3.WithBatchConcurrency(4),
+		v3.WithBatchProgress(func(done, total int) {
+			if total != len(words) {
+				t.Errorf("ERROR: Expected total %d, got %d", len(words), total)
+			}
+			progress = append(progress, done)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if maxInFlight.Load() > 4 {
+		t.Fatalf("ERROR: Expected at most 4 calls in flight, got %d", maxInFlight.Load())
+	}
+	if len(progress) != len(words) || progress[len(progress)-1] != len(words) {
+		t.Fatalf("ERROR: Expected a progress call per item, got %d", len(progress))
+	}
+	for i, result := range results {
+		if result.Index != i || result.Input != words[i] {
+			t.Fatalf("ERROR: Expected result %d in input order, got %d (%s)", i, result.Index, result.Input)
+		}
+		if i == 42 {
+			if !v3.IsInputError(result.Err) {
+				t.Fatalf("ERROR: Expected input error for item 42, got %v", result.Err)
+			}
+			continue
+		}
+		if result.Err != nil || result.Response.Words != words[i] {
+			t.Fatalf("ERROR: Expected %s, got %v %v", words[i], result.Response, result.Err)
+		}
+	}
+}
+
+func TestConvertTo3waBatchAbortsOnFatalError(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusPaymentRequired)
+		w.Write([]byte(`{"error":{"code":"QuotaExceeded","message":"Quota Exceeded"}}`))
+	}))
+	defer srv.Close()
+	api := v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL))
+
+	coordinates := make([]core.Coordinates, 1000)
+	results, err := v3.ConvertTo3waBatch(context.Background(), api, coordinates, nil, v3.WithBatchConcurrency(2))
+	if !v3.IsQuotaError(err) {
+		t.Fatalf("ERROR: Expected quota error, got %v", err)
+	}
+	if calls.Load() > 10 {
+		t.Fatalf("ERROR: Expected the batch to abort early, got %d requests", calls.Load())
+	}
+	if len(results) != len(coordinates) || !errors.Is(results[len(results)-1].Err, v3.ErrBatchAborted) {
+		t.Fatalf("ERROR: Expected remaining items to be aborted, got %v", results[len(results)-1].Err)
+	}
+}
+
+func TestConvertToCoordinatesStream(t *testing.T) {
+	var inFlight, maxInFlight, calls atomic.Int32
+	srv := batchServer(t, &inFlight, &maxInFlight, &calls)
+	api := v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL))
+
+	in := make(chan string)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		defer close(in)
+		for i := 0; i < 1000; i++ {
+			words := fmt.Sprintf("word.number.w%d", i)
+			if i == 50 {
+				words = "revoked.key.here"
+			}
+			select {
+			case in <- words:
+			case <-stop:
+				return
+			}
+		}
+	}()
+	var received int
+	var fatal error
+	for result := range v3.ConvertToCoordinatesStream(context.Background(), api, in, nil, v3.WithBatchConcurrency(3)) {
+		if result.Index != received {
+			t.Fatalf("ERROR: Expected result %d in input order, got %d", received, result.Index)
+		}
+		received++
+		if v3.IsAuthError(result.Err) {
+			fatal = result.Err
+		}
+	}
+	if fatal == nil {
+		t.Fatal("ERROR: Expected the fatal error to be reported")
+	}
+	if received < 51 || received > 60 {
+		t.Fatalf("ERROR: Expected the stream to stop shortly after the fatal error, got %d results", received)
+	}
+}
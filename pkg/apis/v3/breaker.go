@@ -0,0 +1,29 @@
+package v3
+
+import "github.com/what3words/w3w-go-wrapper/pkg/core"
+
+// ErrCircuitOpen is returned, without sending a request, by calls made
+// while the circuit breaker of the API is open.
+var ErrCircuitOpen = core.ErrCircuitOpen
+
+// WithCircuitBreaker stops sending requests to a failing backend once the
+// breaker opens, failing fast with ErrCircuitOpen instead of piling up slow
+// requests. Every attempt, including retries, is checked against the breaker
+// and counted as a failure when it fails with a transport error or a 5xx
+// status code. A breaker can be shared by several APIs calling the same backend.
+//
+// Example usage:
+//
+//	breaker := core.NewCircuitBreaker(core.CircuitBreakerSettings{
+//	    FailureThreshold: 5,
+//	    CoolDown:         30 * time.Second,
+//	    OnStateChange: func(from, to core.CircuitState) {
+//	        log.Printf("w3w circuit %s -> %s", from, to)
+//	    },
+//	})
+//	api := NewAPI("your-api-key", WithCircuitBreaker(breaker))
+func WithCircuitBreaker(breaker *core.CircuitBreaker) APIOption {
+	return func(vs *config) {
+		vs.breaker = breaker
+	}
+}
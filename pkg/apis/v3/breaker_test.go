@@ -0,0 +1,33 @@
+package v3_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+func TestWithCircuitBreaker(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	breaker := core.NewCircuitBreaker(core.CircuitBreakerSettings{FailureThreshold: 1})
+	api := v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL), v3.WithCircuitBreaker(breaker))
+	if _, err := api.AvailableLanguages(context.Background()); errors.Is(err, v3.ErrCircuitOpen) {
+		t.Fatal("ERROR: Expected first call to reach the server")
+	}
+	// The breaker is shared with clones.
+	_, err := api.Clone().ConvertToCoordinates(context.Background(), "filled.count.soap", nil)
+	if !errors.Is(err, v3.ErrCircuitOpen) {
+		t.Fatalf("ERROR: Expected ErrCircuitOpen, got %v", err)
+	}
+	if v3.IsRetryable(err) {
+		t.Fatal("ERROR: Expected ErrCircuitOpen not to be retryable")
+	}
+}
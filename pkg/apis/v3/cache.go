@@ -0,0 +1,46 @@
+package v3
+
+import (
+	"time"
+
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+// Cache stores raw responses of the API. See the w3w-go-wrapper/pkg/cache
+// package for in-memory LRU and file backed implementations.
+type Cache = core.Cache
+
+// cacheableEndpoints are the endpoints returning deterministic
+// responses for the same query parameters.
+var cacheableEndpoints = map[string]bool{
+	EndpointConvertTo3wa:         true,
+	EndpointConvertToCoordinates: true,
+	EndpointAvailableLanguages:   true,
+}
+
+// WithCache caches the responses of the ConvertTo3wa, ConvertToCoordinates
+// (in both JSON and GeoJSON formats) and AvailableLanguages endpoints for ttl.
+// Responses are keyed by URL and API key, so that calls with a different
+// language, locale or format, or made with WithCallBaseURL or WithCallAPIKey,
+// are cached separately. With WithEndpointPool, responses are keyed by the
+// URL and API key of the endpoint which served them, and only served to calls
+// routed to that endpoint. Only successful responses are cached. A ttl of zero or less caches responses forever.
+//
+// Example usage:
+//
+//	api := NewAPI("your-api-key", WithCache(cache.NewLRU(10000), 24*time.Hour))
+func WithCache(cache Cache, ttl time.Duration) APIOption {
+	return func(vs *config) {
+		vs.cache = cache
+		vs.cacheTTL = ttl
+	}
+}
+
+// cacheFor returns the cache to use for the endpoint, nil if the
+// endpoint is not cacheable.
+func (c *config) cacheFor(endpoint string) Cache {
+	if !cacheableEndpoints[endpoint] {
+		return nil
+	}
+	return c.cache
+}
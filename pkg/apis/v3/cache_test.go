@@ -0,0 +1,147 @@
+package v3_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/cache"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+func TestWithCache(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`{"words":"filled.count.soap","language":"` + r.URL.Query().Get("language") + `"}`))
+	}))
+	defer srv.Close()
+
+	api := v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL), v3.WithCache(cache.NewLRU(10), time.Minute))
+	coordinates := core.Coordinates{Lat: 51.520847, Lng: -0.195521}
+	for i := 0; i < 3; i++ {
+		if _, err := api.ConvertTo3wa(context.Background(), coordinates, nil); err != nil {
+			t.Fatalf("ERROR: Got error %v", err)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("ERROR: Expected 1 request, got %d", calls.Load())
+	}
+
+	var meta v3.ResponseMeta
+	resp, err := api.ConvertTo3wa(context.Background(), coordinates, &v3.ConvertAPIOpts{Language: "fr"}, v3.WithResponseMetadata(&meta))
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if calls.Load() != 2 || resp.Language != "fr" || meta.Cached {
+		t.Fatalf("ERROR: Expected a different language to miss the cache, got %d requests", calls.Load())
+	}
+	api.ConvertTo3waGeoJson(context.Background(), coordinates, nil)
+	if calls.Load() != 3 {
+		t.Fatalf("ERROR: Expected GeoJSON format to miss the cache, got %d requests", calls.Load())
+	}
+	api.ConvertTo3wa(context.Background(), coordinates, &v3.ConvertAPIOpts{Language: "fr"}, v3.WithResponseMetadata(&meta))
+	if calls.Load() != 3 || !meta.Cached {
+		t.Fatalf("ERROR: Expected cache hit, got %d requests and %+v", calls.Load(), meta)
+	}
+
+	api.AutoSuggest(context.Background(), "filled.count.so", nil)
+	api.AutoSuggest(context.Background(), "filled.count.so", nil)
+	if calls.Load() != 5 {
+		t.Fatalf("ERROR: Expected autosuggest not to be cached, got %d requests", calls.Load())
+	}
+}
+
+func TestWithCacheCallOverrides(t *testing.T) {
+	var calls atomic.Int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Header.Get(core.HEADER_API_KEY) != "key" && r.Header.Get(core.HEADER_API_KEY) != "tenant-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"code":"InvalidKey","message":"Authentication failed; invalid API key"}}`))
+			return
+		}
+		w.Write([]byte(`{"words":"filled.count.soap"}`))
+	})
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+	other := httptest.NewServer(handler)
+	defer other.Close()
+
+	api := v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL), v3.WithCache(cache.NewLRU(10), time.Minute))
+	coordinates := core.Coordinates{Lat: 51.520847, Lng: -0.195521}
+	if _, err := api.ConvertTo3wa(context.Background(), coordinates, nil); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if _, err := api.ConvertTo3wa(context.Background(), coordinates, nil, v3.WithCallAPIKey("bogus")); err == nil {
+		t.Fatal("ERROR: Expected a call with another API key not to be served from the cache")
+	}
+	if _, err := api.ConvertTo3wa(context.Background(), coordinates, nil, v3.WithCallAPIKey("tenant-key")); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if _, err := api.ConvertTo3wa(context.Background(), coordinates, nil, v3.WithCallBaseURL(other.URL)); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if calls.Load() != 4 {
+		t.Fatalf("ERROR: Expected calls with another API key or base URL to miss the cache, got %d requests", calls.Load())
+	}
+	api.ConvertTo3wa(context.Background(), coordinates, nil, v3.WithCallAPIKey("tenant-key"))
+	if calls.Load() != 4 {
+		t.Fatalf("ERROR: Expected calls with the same API key to hit the cache, got %d requests", calls.Load())
+	}
+}
+
+func TestWithCacheEndpointPool(t *testing.T) {
+	var primaryCalls, secondaryCalls atomic.Int32
+	var primaryDown atomic.Bool
+	primaryDown.Store(true)
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryCalls.Add(1)
+		if primaryDown.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"words":"filled.count.soap"}`))
+	}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		secondaryCalls.Add(1)
+		w.Write([]byte(`{"words":"index.home.raft"}`))
+	}))
+	defer secondary.Close()
+	pool, err := core.NewEndpointPool([]core.Endpoint{
+		{URL: primary.URL},
+		{URL: secondary.URL, APIKey: "secondary-key"},
+	}, core.EndpointPoolSettings{FailureThreshold: 1, ProbeInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	defer pool.Close()
+
+	api := v3.NewAPI("key", v3.WithEndpointPool(pool), v3.WithCache(cache.NewLRU(10), time.Minute))
+	coordinates := core.Coordinates{Lat: 51.520847, Lng: -0.195521}
+	if resp, err := api.ConvertTo3wa(context.Background(), coordinates, nil); err != nil || resp.Words != "index.home.raft" {
+		t.Fatalf("ERROR: Expected the secondary to answer, got %+v, %v", resp, err)
+	}
+	if resp, _ := api.ConvertTo3wa(context.Background(), coordinates, nil); resp.Words != "index.home.raft" || secondaryCalls.Load() != 1 {
+		t.Fatalf("ERROR: Expected the response of the secondary to be cached, got %+v after %d requests", resp, secondaryCalls.Load())
+	}
+
+	primaryDown.Store(false)
+	for deadline := time.Now().Add(time.Second); len(pool.Healthy()) != 2; time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("ERROR: Expected the primary to be re-admitted")
+		}
+	}
+	calls := primaryCalls.Load()
+	if resp, err := api.ConvertTo3wa(context.Background(), coordinates, nil); err != nil || resp.Words != "filled.count.soap" || primaryCalls.Load() != calls+1 {
+		t.Fatalf("ERROR: Expected the response of the secondary not to be served for the primary, got %+v, %v", resp, err)
+	}
+	if api.ConvertTo3wa(context.Background(), coordinates, nil); primaryCalls.Load() != calls+1 {
+		t.Fatalf("ERROR: Expected the response of the primary to be cached, got %d requests", primaryCalls.Load()-calls)
+	}
+}
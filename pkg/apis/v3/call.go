@@ -0,0 +1,107 @@
+package v3
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+// ResponseMeta holds metadata about the response received for a call,
+// such as the status code, headers, server request ID and latency.
+type ResponseMeta = core.ResponseMeta
+
+// CallOption configures a single call to an endpoint of the API. Unlike
+// APIOptions, which apply to every request made by the API, CallOptions
+// only apply to the call they are passed to.
+type CallOption func(*callConfig)
+
+type callConfig struct {
+	meta    *ResponseMeta
+	headers map[string]string
+	timeout time.Duration
+	baseURL string
+}
+
+func newCallConfig(opts []CallOption) callConfig {
+	var call callConfig
+	for _, opt := range opts {
+		opt(&call)
+	}
+	return call
+}
+
+// overridesTarget reports whether the call is sent to another base URL or
+// with other headers, such as another API key, than those of the API.
+// Responses of such calls are kept out of the SquareCache, which is not
+// keyed by either.
+func (call callConfig) overridesTarget() bool {
+	return call.baseURL != "" || len(call.headers) > 0
+}
+
+// WithResponseMetadata fills meta with the metadata of the response received
+// for the call, such as the status code, response headers, request ID and
+// round trip time. It is filled whenever a request was sent, even if the
+// call returns an error.
+//
+// Example usage:
+//
+//	var meta ResponseMeta
+//	resp, err := api.ConvertTo3wa(ctx, coordinates, nil, WithResponseMetadata(&meta))
+//	fmt.Println(meta.StatusCode, meta.RequestID, meta.Duration)
+func WithResponseMetadata(meta *ResponseMeta) CallOption {
+	return func(call *callConfig) {
+		call.meta = meta
+	}
+}
+
+// WithCallHeader sets an HTTP header on the request made by the call,
+// overriding any header of the same name set on the API.
+//
+// Example usage:
+//
+//	resp, err := api.AutoSuggest(ctx, input, nil, WithCallHeader("X-Tenant", tenantID))
+func WithCallHeader(key, value string) CallOption {
+	return func(call *callConfig) {
+		if call.headers == nil {
+			call.headers = make(map[string]string)
+		}
+		setHeaders(call.headers, map[string]string{key: value})
+	}
+}
+
+// WithCallAPIKey authenticates the call with the given API key instead of
+// the API key of the API, for example to make calls on behalf of several
+// tenants with a single API.
+//
+// Example usage:
+//
+//	resp, err := api.ConvertToCoordinates(ctx, words, nil, WithCallAPIKey(tenantKey))
+func WithCallAPIKey(apiKey string) CallOption {
+	return WithCallHeader(core.HEADER_API_KEY, apiKey)
+}
+
+// WithCallTimeout bounds the duration of the call, including any retries and
+// time spent waiting for rate limits. The deadline of the context passed to
+// the call still applies if it is sooner.
+//
+// Example usage:
+//
+//	resp, err := api.AutoSuggest(ctx, input, nil, WithCallTimeout(500*time.Millisecond))
+func WithCallTimeout(timeout time.Duration) CallOption {
+	return func(call *callConfig) {
+		call.timeout = timeout
+	}
+}
+
+// WithCallBaseURL sends the call to a custom base URL instead of the base URL
+// of the API. As with WithCustomBaseURL, the provided URL is suffixed with `/v3`.
+//
+// Example usage:
+//
+//	resp, err := api.AvailableLanguages(ctx, WithCallBaseURL("https://enterprise.example.com"))
+func WithCallBaseURL(baseURL string) CallOption {
+	return func(call *callConfig) {
+		call.baseURL = fmt.Sprintf("%s/v3", baseURL)
+	}
+}
@@ -0,0 +1,119 @@
+package v3_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+func TestWithResponseMetadata(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		w.Header().Set("X-RateLimit-Remaining", "99")
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"words":"filled.count.soap"}`))
+	}))
+	defer srv.Close()
+
+	policy := core.DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	api := v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL), v3.WithRetryPolicy(policy))
+
+	var meta v3.ResponseMeta
+	resp, err := api.ConvertTo3wa(context.Background(), core.Coordinates{Lat: 51.520847, Lng: -0.195521}, nil, v3.WithResponseMetadata(&meta))
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if resp.Words != "filled.count.soap" {
+		t.Fatalf("ERROR: Expected words filled.count.soap, got %s", resp.Words)
+	}
+	if meta.StatusCode != http.StatusOK || meta.Attempts != 2 || meta.RequestID != "req-123" {
+		t.Fatalf("ERROR: Unexpected metadata %+v", meta)
+	}
+	if meta.Endpoint != "/v3/convert-to-3wa" || meta.Header.Get("X-RateLimit-Remaining") != "99" || meta.Duration <= 0 {
+		t.Fatalf("ERROR: Unexpected metadata %+v", meta)
+	}
+}
+
+func TestWithResponseMetadataOnError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"code":"BadWords","message":"Invalid words"}}`))
+	}))
+	defer srv.Close()
+
+	var meta v3.ResponseMeta
+	_, err := v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL)).ConvertToCoordinates(context.Background(), "fill.fake.fill", nil, v3.WithResponseMetadata(&meta))
+	if !errors.Is(err, v3.ErrBadWords) {
+		t.Fatalf("ERROR: Expected BadWords error, got %v", err)
+	}
+	if meta.StatusCode != http.StatusBadRequest || meta.Attempts != 1 {
+		t.Fatalf("ERROR: Unexpected metadata %+v", meta)
+	}
+}
+
+func TestCallOptionsOverrideAPIConfiguration(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Write([]byte(`{"languages":[]}`))
+	}))
+	defer srv.Close()
+
+	api := v3.NewAPI("key", v3.WithCustomBaseURL("http://127.0.0.1:1"), v3.WithCustomHeader("X-Tenant", "default"))
+	_, err := api.AvailableLanguages(context.Background(),
+		v3.WithCallBaseURL(srv.URL),
+		v3.WithCallAPIKey("tenant-key"),
+		v3.WithCallHeader("x-tenant", "acme"),
+	)
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if got.URL.Path != "/v3/available-languages" {
+		t.Fatalf("ERROR: Expected request to the call base URL, got %s", got.URL.Path)
+	}
+	if got.Header.Get(core.HEADER_API_KEY) != "tenant-key" {
+		t.Fatalf("ERROR: Expected call API key, got %q", got.Header.Get(core.HEADER_API_KEY))
+	}
+	if values := got.Header.Values("X-Tenant"); len(values) != 1 || values[0] != "acme" {
+		t.Fatalf("ERROR: Expected call header to replace the API header, got %v", values)
+	}
+
+	// The API configuration is left unchanged.
+	api.SetBaseURL(srv.URL)
+	api.AvailableLanguages(context.Background())
+	if got.Header.Get(core.HEADER_API_KEY) != "key" || got.Header.Get("X-Tenant") != "default" {
+		t.Fatalf("ERROR: Expected API headers, got %v", got.Header)
+	}
+}
+
+func TestWithCallTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	api := v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL))
+	start := time.Now()
+	_, err := api.AvailableLanguages(context.Background(), v3.WithCallTimeout(20*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ERROR: Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("ERROR: Expected call to time out early, took %v", elapsed)
+	}
+}
@@ -0,0 +1,121 @@
+package v3_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+// headerServer replies to every request with the received request headers.
+func headerServer(t *testing.T, received func(http.Header)) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received(r.Header)
+		w.Write([]byte(`{"languages":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSettersAreSafeForConcurrentUse(t *testing.T) {
+	srv := headerServer(t, func(http.Header) {})
+	api := v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			api.SetHeader("X-Tenant", strconv.Itoa(i))
+			api.SetHeaderMap(map[string]string{"X-Other": strconv.Itoa(i)})
+			api.SetRetryPolicy(nil)
+		}(i)
+		go func() {
+			defer wg.Done()
+			if _, err := api.AvailableLanguages(context.Background()); err != nil {
+				t.Errorf("ERROR: Got error %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSetHeaderMapKeepsProtectedHeaders(t *testing.T) {
+	var got http.Header
+	srv := headerServer(t, func(h http.Header) { got = h })
+	api := v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL), v3.WithCustomHeader("X-Old", "old"))
+	api.SetHeaderMap(map[string]string{"X-New": "new"})
+
+	if _, err := api.AvailableLanguages(context.Background()); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if got.Get(core.HEADER_API_KEY) != "key" {
+		t.Fatalf("ERROR: Expected API key header to be kept, got %q", got.Get(core.HEADER_API_KEY))
+	}
+	if got.Get(core.HEADER_WRAPPER) == "" {
+		t.Fatal("ERROR: Expected wrapper header to be kept")
+	}
+	if got.Get("X-New") != "new" || got.Get("X-Old") != "" {
+		t.Fatalf("ERROR: Expected custom headers to be replaced, got %v", got)
+	}
+
+	api.SetHeaderMap(map[string]string{core.HEADER_API_KEY: "other-key"})
+	api.AvailableLanguages(context.Background())
+	if got.Get(core.HEADER_API_KEY) != "other-key" {
+		t.Fatalf("ERROR: Expected API key header to be replaced explicitly, got %q", got.Get(core.HEADER_API_KEY))
+	}
+}
+
+func TestHeadersDifferingInCase(t *testing.T) {
+	var got http.Header
+	srv := headerServer(t, func(h http.Header) { got = h })
+	api := v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL))
+	api.SetHeader("X-Api-Key", "mixed-key")
+	api.SetHeaderMap(map[string]string{"X-New": "new"})
+
+	if _, err := api.AvailableLanguages(context.Background()); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if got.Get(core.HEADER_API_KEY) != "mixed-key" {
+		t.Fatalf("ERROR: Expected API key header set with a different case to be kept, got %q", got.Get(core.HEADER_API_KEY))
+	}
+
+	clone := api.Clone(v3.WithCustomHeader("x-tenant", "a"), v3.WithCustomHeader("X-Tenant", "b"))
+	for i := 0; i < 20; i++ {
+		clone.AvailableLanguages(context.Background())
+		if values := got.Values("X-Tenant"); len(values) != 1 || values[0] != "b" {
+			t.Fatalf("ERROR: Expected the last header differing in case to replace the other, got %v", values)
+		}
+	}
+	clone.AvailableLanguages(context.Background(), v3.WithCallHeader("x-tenant", "c"), v3.WithCallHeader("X-TENANT", "d"))
+	if values := got.Values("X-Tenant"); len(values) != 1 || values[0] != "d" {
+		t.Fatalf("ERROR: Expected the last call header differing in case to replace the other, got %v", values)
+	}
+}
+
+func TestClone(t *testing.T) {
+	var got http.Header
+	srv := headerServer(t, func(h http.Header) { got = h })
+	api := v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL), v3.WithCustomHeader("X-Tenant", "a"))
+	clone := api.Clone(v3.WithCustomHeader("X-Tenant", "b"))
+
+	clone.AvailableLanguages(context.Background())
+	if got.Get("X-Tenant") != "b" || got.Get(core.HEADER_API_KEY) != "key" {
+		t.Fatalf("ERROR: Expected clone to use its own options, got %v", got)
+	}
+	api.AvailableLanguages(context.Background())
+	if got.Get("X-Tenant") != "a" {
+		t.Fatalf("ERROR: Expected original to be unchanged, got %q", got.Get("X-Tenant"))
+	}
+
+	clone.SetHeader("X-Tenant", "c")
+	api.AvailableLanguages(context.Background())
+	if got.Get("X-Tenant") != "a" {
+		t.Fatalf("ERROR: Expected setters on the clone not to affect the original, got %q", got.Get("X-Tenant"))
+	}
+}
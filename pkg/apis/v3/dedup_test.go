@@ -0,0 +1,51 @@
+package v3_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+)
+
+func TestWithRequestDeduplication(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`{"words":"` + r.URL.Query().Get("words") + `","coordinates":{"lat":51.520847,"lng":-0.195521}}`))
+	}))
+	defer srv.Close()
+
+	api := v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL), v3.WithRequestDeduplication())
+	var wg sync.WaitGroup
+	var failures atomic.Int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := api.ConvertToCoordinates(context.Background(), "filled.count.soap", nil)
+			if err != nil || resp.Words != "filled.count.soap" {
+				failures.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if failures.Load() != 0 {
+		t.Fatalf("ERROR: Expected all calls to succeed, %d failed", failures.Load())
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("ERROR: Expected 1 request, got %d", calls.Load())
+	}
+
+	if _, err := api.ConvertToCoordinates(context.Background(), "index.home.raft", nil); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("ERROR: Expected a different call to send a new request, got %d requests", calls.Load())
+	}
+}
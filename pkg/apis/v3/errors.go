@@ -1,26 +1,160 @@
 package v3
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+// HTTPError is returned when the API responds with a non 2xx status code,
+// or with a body that can not be decoded. It wraps the ErrorResponse
+// returned by the API when one is present.
+type HTTPError = core.HTTPError
 
 type ErrorCode string
 
 const (
-	ErrorCodeMissingWords ErrorCode = "MissingWords"
-	ErrorCodeBadWords     ErrorCode = "BadWords"
-	ErrorCodeInvalidKey   ErrorCode = "InvalidKey"
-	ErrorCodeBadLanguage  ErrorCode = "BadLanguage"
-	// TODO: Add all error codes
+	// Authentication and authorisation errors.
+	ErrorCodeInvalidKey            ErrorCode = "InvalidKey"
+	ErrorCodeMissingKey            ErrorCode = "MissingKey"
+	ErrorCodeSuspendedKey          ErrorCode = "SuspendedKey"
+	ErrorCodeInvalidApiVersion     ErrorCode = "InvalidApiVersion"
+	ErrorCodeInvalidReferrer       ErrorCode = "InvalidReferrer"
+	ErrorCodeInvalidIpAddress      ErrorCode = "InvalidIpAddress"
+	ErrorCodeInvalidAppCredentials ErrorCode = "InvalidAppCredentials"
+
+	// Plan errors.
+	ErrorCodeQuotaExceeded ErrorCode = "QuotaExceeded"
+
+	// Input errors.
+	ErrorCodeMissingWords         ErrorCode = "MissingWords"
+	ErrorCodeBadWords             ErrorCode = "BadWords"
+	ErrorCodeBadLanguage          ErrorCode = "BadLanguage"
+	ErrorCodeBadLocale            ErrorCode = "BadLocale"
+	ErrorCodeBadFormat            ErrorCode = "BadFormat"
+	ErrorCodeBadCoordinates       ErrorCode = "BadCoordinates"
+	ErrorCodeMissingCoordinates   ErrorCode = "MissingCoordinates"
+	ErrorCodeBadInput             ErrorCode = "BadInput"
+	ErrorCodeMissingInput         ErrorCode = "MissingInput"
+	ErrorCodeBadInputType         ErrorCode = "BadInputType"
+	ErrorCodeBadNResults          ErrorCode = "BadNResults"
+	ErrorCodeBadNFocusResults     ErrorCode = "BadNFocusResults"
+	ErrorCodeBadFocus             ErrorCode = "BadFocus"
+	ErrorCodeBadClipToCountry     ErrorCode = "BadClipToCountry"
+	ErrorCodeBadClipToCircle      ErrorCode = "BadClipToCircle"
+	ErrorCodeBadClipToBoundingBox ErrorCode = "BadClipToBoundingBox"
+	ErrorCodeBadClipToPolygon     ErrorCode = "BadClipToPolygon"
+	ErrorCodeBadPreferLand        ErrorCode = "BadPreferLand"
+	ErrorCodeBadBoundingBox       ErrorCode = "BadBoundingBox"
+	ErrorCodeMissingBoundingBox   ErrorCode = "MissingBoundingBox"
+	ErrorCodeBadBoundingBoxTooBig ErrorCode = "BadBoundingBoxTooBig"
+	ErrorCodeDuplicateParameter   ErrorCode = "DuplicateParameter"
+
+	// Server errors.
+	ErrorCodeInternalServerError ErrorCode = "InternalServerError"
 )
 
+// Sentinel errors for each ErrorCode, to be used with `errors.Is`.
+// An ErrorResponse matches the sentinel with the same Code.
+//
+// Example usage:
+//
+//	if errors.Is(err, ErrInvalidKey) {
+//		// handle invalid key
+//	}
+var (
+	ErrInvalidKey            = &ErrorResponse{Code: ErrorCodeInvalidKey}
+	ErrMissingKey            = &ErrorResponse{Code: ErrorCodeMissingKey}
+	ErrSuspendedKey          = &ErrorResponse{Code: ErrorCodeSuspendedKey}
+	ErrInvalidApiVersion     = &ErrorResponse{Code: ErrorCodeInvalidApiVersion}
+	ErrInvalidReferrer       = &ErrorResponse{Code: ErrorCodeInvalidReferrer}
+	ErrInvalidIpAddress      = &ErrorResponse{Code: ErrorCodeInvalidIpAddress}
+	ErrInvalidAppCredentials = &ErrorResponse{Code: ErrorCodeInvalidAppCredentials}
+	ErrQuotaExceeded         = &ErrorResponse{Code: ErrorCodeQuotaExceeded}
+	ErrMissingWords          = &ErrorResponse{Code: ErrorCodeMissingWords}
+	ErrBadWords              = &ErrorResponse{Code: ErrorCodeBadWords}
+	ErrBadLanguage           = &ErrorResponse{Code: ErrorCodeBadLanguage}
+	ErrBadLocale             = &ErrorResponse{Code: ErrorCodeBadLocale}
+	ErrBadFormat             = &ErrorResponse{Code: ErrorCodeBadFormat}
+	ErrBadCoordinates        = &ErrorResponse{Code: ErrorCodeBadCoordinates}
+	ErrMissingCoordinates    = &ErrorResponse{Code: ErrorCodeMissingCoordinates}
+	ErrBadInput              = &ErrorResponse{Code: ErrorCodeBadInput}
+	ErrMissingInput          = &ErrorResponse{Code: ErrorCodeMissingInput}
+	ErrBadInputType          = &ErrorResponse{Code: ErrorCodeBadInputType}
+	ErrBadNResults           = &ErrorResponse{Code: ErrorCodeBadNResults}
+	ErrBadNFocusResults      = &ErrorResponse{Code: ErrorCodeBadNFocusResults}
+	ErrBadFocus              = &ErrorResponse{Code: ErrorCodeBadFocus}
+	ErrBadClipToCountry      = &ErrorResponse{Code: ErrorCodeBadClipToCountry}
+	ErrBadClipToCircle       = &ErrorResponse{Code: ErrorCodeBadClipToCircle}
+	ErrBadClipToBoundingBox  = &ErrorResponse{Code: ErrorCodeBadClipToBoundingBox}
+	ErrBadClipToPolygon      = &ErrorResponse{Code: ErrorCodeBadClipToPolygon}
+	ErrBadPreferLand         = &ErrorResponse{Code: ErrorCodeBadPreferLand}
+	ErrBadBoundingBox        = &ErrorResponse{Code: ErrorCodeBadBoundingBox}
+	ErrMissingBoundingBox    = &ErrorResponse{Code: ErrorCodeMissingBoundingBox}
+	ErrBadBoundingBoxTooBig  = &ErrorResponse{Code: ErrorCodeBadBoundingBoxTooBig}
+	ErrDuplicateParameter    = &ErrorResponse{Code: ErrorCodeDuplicateParameter}
+	ErrInternalServerError   = &ErrorResponse{Code: ErrorCodeInternalServerError}
+)
+
+type errorClass int
+
+const (
+	errorClassUnknown errorClass = iota
+	errorClassAuth
+	errorClassQuota
+	errorClassInput
+	errorClassServer
+)
+
+var errorClasses = map[ErrorCode]errorClass{
+	ErrorCodeInvalidKey:            errorClassAuth,
+	ErrorCodeMissingKey:            errorClassAuth,
+	ErrorCodeSuspendedKey:          errorClassAuth,
+	ErrorCodeInvalidApiVersion:     errorClassAuth,
+	ErrorCodeInvalidReferrer:       errorClassAuth,
+	ErrorCodeInvalidIpAddress:      errorClassAuth,
+	ErrorCodeInvalidAppCredentials: errorClassAuth,
+	ErrorCodeQuotaExceeded:         errorClassQuota,
+	ErrorCodeMissingWords:          errorClassInput,
+	ErrorCodeBadWords:              errorClassInput,
+	ErrorCodeBadLanguage:           errorClassInput,
+	ErrorCodeBadLocale:             errorClassInput,
+	ErrorCodeBadFormat:             errorClassInput,
+	ErrorCodeBadCoordinates:        errorClassInput,
+	ErrorCodeMissingCoordinates:    errorClassInput,
+	ErrorCodeBadInput:              errorClassInput,
+	ErrorCodeMissingInput:          errorClassInput,
+	ErrorCodeBadInputType:          errorClassInput,
+	ErrorCodeBadNResults:           errorClassInput,
+	ErrorCodeBadNFocusResults:      errorClassInput,
+	ErrorCodeBadFocus:              errorClassInput,
+	ErrorCodeBadClipToCountry:      errorClassInput,
+	ErrorCodeBadClipToCircle:       errorClassInput,
+	ErrorCodeBadClipToBoundingBox:  errorClassInput,
+	ErrorCodeBadClipToPolygon:      errorClassInput,
+	ErrorCodeBadPreferLand:         errorClassInput,
+	ErrorCodeBadBoundingBox:        errorClassInput,
+	ErrorCodeMissingBoundingBox:    errorClassInput,
+	ErrorCodeBadBoundingBoxTooBig:  errorClassInput,
+	ErrorCodeDuplicateParameter:    errorClassInput,
+	ErrorCodeInternalServerError:   errorClassServer,
+}
+
 // ErrorResponse models format of the error response
 // recived from the API when a non 200 status code
 // is recieved.
 // Implements std `error` interface, so that it
-// can be returned as an error.
+// can be returned as an error. It is always returned
+// wrapped in an HTTPError.
 //
 // Example inferance:
 //
-//	errResp, ok := err.(*ErrorResponse)
+//	var errResp *ErrorResponse
+//	ok := errors.As(err, &errResp)
 type ErrorResponse struct {
 	// Code can be ustized to programatically determine
 	// the error.
@@ -33,3 +167,85 @@ type ErrorResponse struct {
 func (er ErrorResponse) Error() string {
 	return fmt.Sprintf("api: got error response '%s' with message '%s'", er.Code, er.Message)
 }
+
+// Is reports whether the target is an ErrorResponse with the same Code,
+// so that `errors.Is(err, ErrInvalidKey)` matches any InvalidKey response.
+func (er *ErrorResponse) Is(target error) bool {
+	t, ok := target.(*ErrorResponse)
+	return ok && er != nil && t != nil && er.Code == t.Code
+}
+
+// asError returns the ErrorResponse as an error, making sure a nil
+// ErrorResponse results in a nil error interface.
+func (er *ErrorResponse) asError() error {
+	if er == nil {
+		return nil
+	}
+	return er
+}
+
+// classify determines the class of the error from the wrapped ErrorResponse
+// code, falling back to the HTTP status code when no code is available.
+func classify(err error) errorClass {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return errorClassInput
+	}
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) {
+		if class, ok := errorClasses[errResp.Code]; ok {
+			return class
+		}
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		switch {
+		case httpErr.StatusCode == http.StatusUnauthorized, httpErr.StatusCode == http.StatusForbidden:
+			return errorClassAuth
+		case httpErr.StatusCode == http.StatusPaymentRequired:
+			return errorClassQuota
+		case httpErr.StatusCode >= 500:
+			return errorClassServer
+		case httpErr.StatusCode >= 400 && httpErr.StatusCode != http.StatusTooManyRequests:
+			return errorClassInput
+		}
+	}
+	return errorClassUnknown
+}
+
+// IsRetryable reports whether the request that failed with err may succeed
+// if retried unchanged. This is the case for server errors, rate limiting
+// (429) and network errors, but not for cancelled or expired contexts.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if classify(err) == errorClassServer {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// IsAuthError reports whether err was caused by a missing, invalid or
+// suspended API key, or by the key not being allowed to make the request.
+func IsAuthError(err error) bool {
+	return err != nil && classify(err) == errorClassAuth
+}
+
+// IsQuotaError reports whether err was caused by the plan quota of the
+// API key being exceeded.
+func IsQuotaError(err error) bool {
+	return err != nil && classify(err) == errorClassQuota
+}
+
+// IsInputError reports whether err was caused by invalid parameters
+// sent to the API, such as bad words, coordinates or clipping options,
+// including parameters rejected by client side validation.
+func IsInputError(err error) bool {
+	return err != nil && classify(err) == errorClassInput
+}
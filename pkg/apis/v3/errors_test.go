@@ -0,0 +1,120 @@
+package v3_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+)
+
+func TestHTTPErrorWrapsErrorResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"code":"BadWords","message":"Invalid or non-existent 3 word address"}}`))
+	}))
+	defer srv.Close()
+
+	_, err := v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL)).ConvertToCoordinates(context.Background(), "fill.fake.fill", nil)
+	var httpErr *v3.HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("ERROR: Expected *v3.HTTPError, got %T (%v)", err, err)
+	}
+	if httpErr.StatusCode != http.StatusBadRequest || httpErr.Endpoint != "/v3/convert-to-coordinates" {
+		t.Fatalf("ERROR: Unexpected status %d or endpoint %s", httpErr.StatusCode, httpErr.Endpoint)
+	}
+	var errResp *v3.ErrorResponse
+	if !errors.As(err, &errResp) {
+		t.Fatalf("ERROR: Expected *v3.ErrorResponse to be wrapped, got %v", httpErr.Err)
+	}
+	if errResp.Code != v3.ErrorCodeBadWords {
+		t.Fatalf("ERROR: Expected code %s, got %s", v3.ErrorCodeBadWords, errResp.Code)
+	}
+}
+
+func TestHTTPErrorWithoutErrorResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"not found"}`))
+	}))
+	defer srv.Close()
+
+	_, err := v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL)).AvailableLanguages(context.Background())
+	var httpErr *v3.HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("ERROR: Expected *v3.HTTPError, got %T (%v)", err, err)
+	}
+	if httpErr.Err != nil {
+		t.Fatalf("ERROR: Expected no wrapped error, got %#v", httpErr.Err)
+	}
+	var errResp *v3.ErrorResponse
+	if errors.As(err, &errResp) {
+		t.Fatal("ERROR: Expected no *v3.ErrorResponse to be found")
+	}
+}
+
+func TestErrorsIsMatchesErrorCode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"code":"InvalidKey","message":"Authentication failed; invalid API key"}}`))
+	}))
+	defer srv.Close()
+
+	_, err := v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL)).AvailableLanguages(context.Background())
+	if !errors.Is(err, v3.ErrInvalidKey) {
+		t.Fatalf("ERROR: Expected errors.Is to match ErrInvalidKey, got %v", err)
+	}
+	if errors.Is(err, v3.ErrMissingKey) {
+		t.Fatal("ERROR: Expected errors.Is not to match ErrMissingKey")
+	}
+}
+
+func TestErrorClassification(t *testing.T) {
+	wrap := func(status int, code v3.ErrorCode) error {
+		var inner error
+		if code != "" {
+			inner = &v3.ErrorResponse{Code: code}
+		}
+		return &v3.HTTPError{StatusCode: status, Err: inner}
+	}
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+		auth      bool
+		input     bool
+		quota     bool
+	}{
+		{"Nil", nil, false, false, false, false},
+		{"InvalidKey", wrap(401, v3.ErrorCodeInvalidKey), false, true, false, false},
+		{"SuspendedKey", wrap(401, v3.ErrorCodeSuspendedKey), false, true, false, false},
+		{"QuotaExceeded", wrap(402, v3.ErrorCodeQuotaExceeded), false, false, false, true},
+		{"BadCoordinates", wrap(400, v3.ErrorCodeBadCoordinates), false, false, true, false},
+		{"BadClipToPolygon", wrap(400, v3.ErrorCodeBadClipToPolygon), false, false, true, false},
+		{"InternalServerError", wrap(500, v3.ErrorCodeInternalServerError), true, false, false, false},
+		{"BadGateway", wrap(502, ""), true, false, false, false},
+		{"TooManyRequests", wrap(429, ""), true, false, false, false},
+		{"Forbidden", wrap(403, ""), false, true, false, false},
+		{"Network", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true, false, false, false},
+		{"Deadline", context.DeadlineExceeded, false, false, false, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := v3.IsRetryable(test.err); got != test.retryable {
+				t.Fatalf("ERROR: IsRetryable expected %v got %v", test.retryable, got)
+			}
+			if got := v3.IsAuthError(test.err); got != test.auth {
+				t.Fatalf("ERROR: IsAuthError expected %v got %v", test.auth, got)
+			}
+			if got := v3.IsInputError(test.err); got != test.input {
+				t.Fatalf("ERROR: IsInputError expected %v got %v", test.input, got)
+			}
+			if got := v3.IsQuotaError(test.err); got != test.quota {
+				t.Fatalf("ERROR: IsQuotaError expected %v got %v", test.quota, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package v3
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TextDirection is the direction in which the words of a language are written.
+type TextDirection string
+
+const (
+	TextDirectionLTR TextDirection = "ltr"
+	TextDirectionRTL TextDirection = "rtl"
+)
+
+// scripts maps languages and locales to the ISO 15924 code of the script
+// their 3 word addresses are written in. Locales fall back to the script of
+// their language, and codes missing from the table to Latn.
+var scripts = map[string]string{
+	"am":    "Ethi",
+	"ar":    "Arab",
+	"bg":    "Cyrl",
+	"bn":    "Beng",
+	"el":    "Grek",
+	"fa":    "Arab",
+	"gu":    "Gujr",
+	"he":    "Hebr",
+	"hi":    "Deva",
+	"ja":    "Jpan",
+	"kk":    "Cyrl",
+	"km":    "Khmr",
+	"kn":    "Knda",
+	"ko":    "Kore",
+	"lo":    "Laoo",
+	"mk":    "Cyrl",
+	"ml":    "Mlym",
+	"mn":    "Cyrl",
+	"mr":    "Deva",
+	"ne":    "Deva",
+	"or":    "Orya",
+	"pa":    "Guru",
+	"ru":    "Cyrl",
+	"si":    "Sinh",
+	"sr":    "Cyrl",
+	"ta":    "Taml",
+	"te":    "Telu",
+	"th":    "Thai",
+	"ti":    "Ethi",
+	"uk":    "Cyrl",
+	"ur":    "Arab",
+	"zh":    "Hans",
+	"kk_cy": "Cyrl",
+	"kk_la": "Latn",
+	"mn_cy": "Cyrl",
+	"mn_la": "Latn",
+	"oo_cy": "Cyrl",
+	"oo_la": "Latn",
+	"zh_si": "Hans",
+	"zh_tr": "Hant",
+}
+
+// rtlScripts are the scripts written from right to left.
+var rtlScripts = map[string]bool{
+	"Arab": true,
+	"Hebr": true,
+}
+
+func scriptOf(language, locale string) string {
+	if script, ok := scripts[locale]; ok {
+		return script
+	}
+	if script, ok := scripts[language]; ok {
+		return script
+	}
+	return "Latn"
+}
+
+// CatalogEntry describes a language or one of its locales.
+type CatalogEntry struct {
+	// Language is the ISO 639-1 code of the language.
+	Language string
+	// Locale is the code of the locale, empty for the language itself.
+	Locale     string
+	Name       string
+	NativeName string
+	// Script is the ISO 15924 code of the script, such as Latn or Cyrl.
+	Script    string
+	Direction TextDirection
+}
+
+// Code returns the code of the locale, or of the language for entries
+// describing a language.
+func (ce CatalogEntry) Code() string {
+	if ce.Locale != "" {
+		return ce.Locale
+	}
+	return ce.Language
+}
+
+// catalogRetryDelay is the delay after which a LanguageCatalog fetches the
+// languages again when they failed to be fetched, or the ttl if shorter.
+const catalogRetryDelay = 30 * time.Second
+
+// LanguageCatalog holds the languages and locales returned by the
+// AvailableLanguages endpoint, with the script and text direction of each,
+// fetching them on first use and again once they expire. It is safe for
+// concurrent use and can be shared by several APIs.
+type LanguageCatalog struct {
+	api API
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries []CatalogEntry
+	index   map[string]int
+	expires time.Time
+	// err is the error of the last fetch, nil if it succeeded.
+	err error
+	// loading is closed once the fetch in flight completes, nil when no
+	// fetch is in flight.
+	loading chan struct{}
+}
+
+// NewLanguageCatalog creates a LanguageCatalog fetching the languages with
+// the given API, and fetching them again after ttl. A ttl of zero or less
+// means the languages are fetched once.
+//
+// Example usage:
+//
+//	catalog := NewLanguageCatalog(api, 24*time.Hour)
+//	entry, err := catalog.Lookup(ctx, "mn_cy")
+func NewLanguageCatalog(api API, ttl time.Duration) *LanguageCatalog {
+	return &LanguageCatalog{api: api, ttl: ttl}
+}
+
+// WithLanguageCatalog checks the Language and Locale options of the convert
+// and autosuggest endpoints against the given LanguageCatalog before a
+// request is sent, rejecting unavailable ones with a ValidationError. The
+// check is skipped when the catalog can not be loaded, leaving the API to
+// report unsupported languages, and with WithoutValidation.
+//
+// Example usage:
+//
+//	api := NewAPI("your-api-key")
+//	api = api.Clone(WithLanguageCatalog(NewLanguageCatalog(api, 24*time.Hour)))
+func WithLanguageCatalog(catalog *LanguageCatalog) APIOption {
+	return func(vs *config) {
+		vs.languageCatalog = catalog
+	}
+}
+
+// Load fetches the languages unless they are loaded and have not expired.
+// Once loaded, languages which fail to be fetched again are kept, so that
+// Load only fails when no languages are available. After a failed fetch,
+// the languages are not fetched again for 30 seconds, or the ttl if
+// shorter, and Load returns the error of that fetch in the meantime.
+// Concurrent calls share a single request.
+func (lc *LanguageCatalog) Load(ctx context.Context) error {
+	return lc.load(ctx, false)
+}
+
+// Refresh fetches the languages, even if they have not expired. The
+// previous languages are kept when the request fails.
+func (lc *LanguageCatalog) Refresh(ctx context.Context) error {
+	return lc.load(ctx, true)
+}
+
+// load waits for the fetch in flight, if any, then fetches the languages
+// when forced or when they have expired. The request is made without
+// holding the lock, so that the loaded languages remain readable.
+func (lc *LanguageCatalog) load(ctx context.Context, force bool) error {
+	for {
+		lc.mu.Lock()
+		if loading := lc.loading; loading != nil {
+			lc.mu.Unlock()
+			select {
+			case <-loading:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			if !force {
+				continue
+			}
+			// The fetch in flight may have started before the call to
+			// Refresh, which fetches the languages again.
+			lc.mu.Lock()
+			if lc.loading != nil {
+				lc.mu.Unlock()
+				continue
+			}
+		} else if !force && (time.Now().Before(lc.expires) || (lc.index != nil && lc.ttl <= 0)) {
+			defer lc.mu.Unlock()
+			if lc.index == nil {
+				return lc.err
+			}
+			return nil
+		}
+		loading := make(chan struct{})
+		lc.loading = loading
+		lc.mu.Unlock()
+
+		err := lc.fetch(ctx)
+
+		lc.mu.Lock()
+		lc.loading = nil
+		close(loading)
+		loaded := lc.index != nil
+		lc.mu.Unlock()
+		if err != nil && (force || !loaded) {
+			return err
+		}
+		return nil
+	}
+}
+
+// fetch requests the languages and stores them. A failed request is only
+// retried after catalogRetryDelay, unless it failed because ctx is done.
+func (lc *LanguageCatalog) fetch(ctx context.Context) error {
+	resp, err := lc.api.AvailableLanguages(ctx)
+	if err != nil {
+		lc.mu.Lock()
+		defer lc.mu.Unlock()
+		if ctx.Err() == nil {
+			delay := catalogRetryDelay
+			if lc.ttl > 0 {
+				delay = min(delay, lc.ttl)
+			}
+			lc.err, lc.expires = err, time.Now().Add(delay)
+		}
+		return err
+	}
+	var entries []CatalogEntry
+	index := make(map[string]int)
+	add := func(entry CatalogEntry) {
+		entry.Script = scriptOf(entry.Language, entry.Locale)
+		entry.Direction = TextDirectionLTR
+		if rtlScripts[entry.Script] {
+			entry.Direction = TextDirectionRTL
+		}
+		index[entry.Code()] = len(entries)
+		entries = append(entries, entry)
+	}
+	for _, language := range resp.Languages {
+		add(CatalogEntry{Language: language.Code, Name: language.Name, NativeName: language.NativeName})
+		for _, locale := range language.Locales {
+			add(CatalogEntry{Language: language.Code, Locale: locale.Code, Name: locale.Name, NativeName: locale.NativeName})
+		}
+	}
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	lc.entries, lc.index, lc.err = entries, index, nil
+	lc.expires = time.Now().Add(lc.ttl)
+	return nil
+}
+
+// Languages returns the available languages, without their locales, in the
+// order of the API.
+func (lc *LanguageCatalog) Languages(ctx context.Context) ([]CatalogEntry, error) {
+	if err := lc.Load(ctx); err != nil {
+		return nil, err
+	}
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	var languages []CatalogEntry
+	for _, entry := range lc.entries {
+		if entry.Locale == "" {
+			languages = append(languages, entry)
+		}
+	}
+	return languages, nil
+}
+
+// Locales returns the locales of the given language, which are empty for
+// most languages. Unavailable languages are reported with an ErrorResponse
+// matching ErrBadLanguage.
+func (lc *LanguageCatalog) Locales(ctx context.Context, language string) ([]CatalogEntry, error) {
+	if _, err := lc.Lookup(ctx, language); err != nil {
+		return nil, err
+	}
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	var locales []CatalogEntry
+	for _, entry := range lc.entries {
+		if entry.Language == language && entry.Locale != "" {
+			locales = append(locales, entry)
+		}
+	}
+	return locales, nil
+}
+
+// Lookup returns the language or locale with the given code, such as en or
+// mn_cy. Unavailable codes are reported with an ErrorResponse matching
+// ErrBadLanguage, or ErrBadLocale for codes in the form of a locale.
+func (lc *LanguageCatalog) Lookup(ctx context.Context, code string) (CatalogEntry, error) {
+	if err := lc.Load(ctx); err != nil {
+		return CatalogEntry{}, err
+	}
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	if i, ok := lc.index[code]; ok {
+		return lc.entries[i], nil
+	}
+	if regexLocale.MatchString(code) {
+		return CatalogEntry{}, &ErrorResponse{Code: ErrorCodeBadLocale, Message: fmt.Sprintf("%q is not an available locale", code)}
+	}
+	return CatalogEntry{}, &ErrorResponse{Code: ErrorCodeBadLanguage, Message: fmt.Sprintf("%q is not an available language", code)}
+}
+
+// Validate checks the language and locale, either of which may be empty,
+// are available, returning a ValidationError for those which are not, or
+// the error of loading the catalog.
+func (lc *LanguageCatalog) Validate(ctx context.Context, language, locale string) error {
+	var ve ValidationError
+	if err := lc.check(ctx, &ve, language, locale); err != nil {
+		return err
+	}
+	return ve.Err()
+}
+
+// check records the language and locale which are not available. The
+// format of the codes, and whether the locale belongs to the language, are
+// left to validateLanguage.
+func (lc *LanguageCatalog) check(ctx context.Context, ve *ValidationError, language, locale string) error {
+	if lc == nil || (language == "" && locale == "") {
+		return nil
+	}
+	if err := lc.Load(ctx); err != nil {
+		return err
+	}
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	if _, ok := lc.index[language]; language != "" && !ok {
+		ve.Add("Language", "%q is not an available language", language)
+	}
+	if i, ok := lc.index[locale]; locale != "" && (!ok || lc.entries[i].Locale == "") {
+		ve.Add("Locale", "%q is not an available locale", locale)
+	}
+	return nil
+}
+
+// checkCatalog records the language and locale missing from the language
+// catalog of the API, if any. The check is skipped when the catalog can not
+// be loaded, leaving the API to report them. A catalog which failed to load
+// is not fetched again by every request, see LanguageCatalog.Load.
+func (c *config) checkCatalog(ctx context.Context, ve *ValidationError, language, locale string) {
+	_ = c.languageCatalog.check(ctx, ve, language, locale)
+}
@@ -0,0 +1,209 @@
+package v3_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+	"github.com/what3words/w3w-go-wrapper/pkg/w3wtest"
+)
+
+func catalogServer() *w3wtest.Server {
+	return w3wtest.NewServer(w3wtest.WithLanguages(
+		v3.Language{Code: "en", Name: "English", NativeName: "English"},
+		v3.Language{Code: "ar", Name: "Arabic", NativeName: "عربي"},
+		v3.Language{Code: "oo", Name: "Oirat", NativeName: "Ойрад", Locales: []v3.Locale{
+			{Code: "oo_cy", Name: "Oirat (Cyrillic)", NativeName: "Ойрад (Кирилл)"},
+			{Code: "oo_la", Name: "Oirat (Latin)", NativeName: "Oirad (Latin)"},
+		}},
+	))
+}
+
+func TestLanguageCatalog(t *testing.T) {
+	srv := catalogServer()
+	defer srv.Close()
+	ctx := context.Background()
+	catalog := v3.NewLanguageCatalog(v3.NewAPI("test-key", v3.WithCustomBaseURL(srv.URL)), 0)
+
+	languages, err := catalog.Languages(ctx)
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if len(languages) != 3 || languages[2].Code() != "oo" {
+		t.Fatalf("ERROR: Expected en, ar and oo, got %+v", languages)
+	}
+	locales, err := catalog.Locales(ctx, "oo")
+	if err != nil || len(locales) != 2 {
+		t.Fatalf("ERROR: Expected the 2 locales of oo, got %+v, %v", locales, err)
+	}
+	entry, err := catalog.Lookup(ctx, "oo_cy")
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if entry.Language != "oo" || entry.Script != "Cyrl" || entry.Direction != v3.TextDirectionLTR {
+		t.Fatalf("ERROR: Expected oo_cy to be an ltr Cyrillic locale of oo, got %+v", entry)
+	}
+	if entry, _ := catalog.Lookup(ctx, "ar"); entry.Script != "Arab" || entry.Direction != v3.TextDirectionRTL {
+		t.Fatalf("ERROR: Expected ar to be written rtl in the Arabic script, got %+v", entry)
+	}
+	if _, err := catalog.Lookup(ctx, "xx"); !errors.Is(err, v3.ErrBadLanguage) {
+		t.Fatalf("ERROR: Expected ErrBadLanguage, got %v", err)
+	}
+	if _, err := catalog.Lookup(ctx, "oo_xx"); !errors.Is(err, v3.ErrBadLocale) {
+		t.Fatalf("ERROR: Expected ErrBadLocale, got %v", err)
+	}
+	if n := len(srv.RequestsTo(v3.EndpointAvailableLanguages)); n != 1 {
+		t.Fatalf("ERROR: Expected the languages to be fetched once, got %d requests", n)
+	}
+
+	if err := catalog.Refresh(ctx); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if n := len(srv.RequestsTo(v3.EndpointAvailableLanguages)); n != 2 {
+		t.Fatalf("ERROR: Expected Refresh to fetch the languages, got %d requests", n)
+	}
+}
+
+func TestLanguageCatalogFailure(t *testing.T) {
+	srv := catalogServer()
+	defer srv.Close()
+	ctx := context.Background()
+	api := v3.NewAPI("test-key", v3.WithCustomBaseURL(srv.URL))
+	catalog := v3.NewLanguageCatalog(api, time.Hour)
+	api = api.Clone(v3.WithLanguageCatalog(catalog))
+	srv.Override(v3.EndpointAvailableLanguages, w3wtest.Error(http.StatusInternalServerError, v3.ErrorCodeInternalServerError, "Server Error"))
+
+	for i := 0; i < 5; i++ {
+		if _, err := api.ConvertTo3wa(ctx, core.Coordinates{Lat: 51.520847, Lng: -0.195521}, &v3.ConvertAPIOpts{Language: "en"}); err != nil {
+			t.Fatalf("ERROR: Expected the check to be skipped when the catalog fails to load, got %v", err)
+		}
+	}
+	if err := catalog.Load(ctx); !errors.Is(err, v3.ErrInternalServerError) {
+		t.Fatalf("ERROR: Expected Load to return the error of the failed fetch, got %v", err)
+	}
+	if n := len(srv.RequestsTo(v3.EndpointAvailableLanguages)); n != 1 {
+		t.Fatalf("ERROR: Expected a failed fetch not to be retried right away, got %d requests", n)
+	}
+
+	srv.ClearOverrides()
+	if err := catalog.Refresh(ctx); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if _, err := api.ConvertTo3wa(ctx, core.Coordinates{Lat: 51.520847, Lng: -0.195521}, &v3.ConvertAPIOpts{Language: "de"}); err == nil {
+		t.Fatal("ERROR: Expected de to be rejected once the catalog is loaded")
+	}
+	if n := len(srv.RequestsTo(v3.EndpointAvailableLanguages)); n != 2 {
+		t.Fatalf("ERROR: Expected Refresh to fetch the languages, got %d requests", n)
+	}
+}
+
+func TestLanguageCatalogConcurrentLoad(t *testing.T) {
+	srv := catalogServer()
+	defer srv.Close()
+	ctx := context.Background()
+	catalog := v3.NewLanguageCatalog(v3.NewAPI("test-key", v3.WithCustomBaseURL(srv.URL)), 0)
+	languages := w3wtest.JSON(http.StatusOK, map[string][]v3.Language{"languages": {{Code: "en", Name: "English", NativeName: "English"}}})
+	srv.Override(v3.EndpointAvailableLanguages, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		languages.ServeHTTP(w, r)
+	}))
+
+	var wg sync.WaitGroup
+	count := 10
+	wg.Add(count)
+	for i := 0; i < count; i++ {
+		go func() {
+			defer wg.Done()
+			if _, err := catalog.Lookup(ctx, "en"); err != nil {
+				t.Errorf("ERROR: Got error %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := len(srv.RequestsTo(v3.EndpointAvailableLanguages)); n != 1 {
+		t.Fatalf("ERROR: Expected concurrent lookups to share a single fetch, got %d requests", n)
+	}
+}
+
+func TestLanguageCatalogValidate(t *testing.T) {
+	srv := catalogServer()
+	defer srv.Close()
+	ctx := context.Background()
+	catalog := v3.NewLanguageCatalog(v3.NewAPI("test-key", v3.WithCustomBaseURL(srv.URL)), 0)
+
+	tests := []struct {
+		opts   v3.AutoSuggestOpts
+		fields []string
+	}{
+		{opts: v3.AutoSuggestOpts{}},
+		{opts: v3.AutoSuggestOpts{Language: "oo", Locale: "oo_la"}},
+		{opts: v3.AutoSuggestOpts{Language: "de"}, fields: []string{"Language"}},
+		{opts: v3.AutoSuggestOpts{Language: "oo", Locale: "oo_xx"}, fields: []string{"Locale"}},
+		{opts: v3.AutoSuggestOpts{Locale: "en"}, fields: []string{"Locale", "Locale"}},
+		{opts: v3.AutoSuggestOpts{Language: "en", Locale: "oo_cy", NResults: core.Int(0)}, fields: []string{"Locale", "NResults"}},
+	}
+	for _, test := range tests {
+		err := test.opts.ValidateWith(ctx, catalog)
+		var ve *v3.ValidationError
+		if len(test.fields) == 0 {
+			if err != nil {
+				t.Fatalf("ERROR: Expected %+v to be valid, got %v", test.opts, err)
+			}
+			continue
+		}
+		if !errors.As(err, &ve) {
+			t.Fatalf("ERROR: Expected a ValidationError for %+v, got %v", test.opts, err)
+		}
+		var fields []string
+		for _, fe := range ve.Errors {
+			fields = append(fields, fe.Field)
+		}
+		if len(fields) != len(test.fields) {
+			t.Fatalf("ERROR: Expected errors for %v with %+v, got %v", test.fields, test.opts, err)
+		}
+		for i := range fields {
+			if fields[i] != test.fields[i] {
+				t.Fatalf("ERROR: Expected errors for %v with %+v, got %v", test.fields, test.opts, err)
+			}
+		}
+	}
+	if err := (v3.ConvertAPIOpts{Language: "ar"}).ValidateWith(ctx, catalog); err != nil {
+		t.Fatalf("ERROR: Expected ar to be valid, got %v", err)
+	}
+	if err := (v3.ConvertAPIOpts{Language: "fr"}).ValidateWith(ctx, nil); err != nil {
+		t.Fatalf("ERROR: Expected only the format to be checked without catalog, got %v", err)
+	}
+}
+
+func TestWithLanguageCatalog(t *testing.T) {
+	srv := catalogServer()
+	defer srv.Close()
+	ctx := context.Background()
+	api := v3.NewAPI("test-key", v3.WithCustomBaseURL(srv.URL))
+	api = api.Clone(v3.WithLanguageCatalog(v3.NewLanguageCatalog(api, 0)))
+
+	_, err := api.ConvertTo3wa(ctx, core.Coordinates{Lat: 51.520847, Lng: -0.195521}, &v3.ConvertAPIOpts{Language: "de"})
+	var ve *v3.ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("ERROR: Expected a ValidationError, got %v", err)
+	}
+	if _, err := api.AutoSuggest(ctx, "index.home.ra", &v3.AutoSuggestOpts{Language: "oo", Locale: "oo_xx"}); !errors.As(err, &ve) {
+		t.Fatalf("ERROR: Expected a ValidationError, got %v", err)
+	}
+	if n := len(srv.RequestsTo(v3.EndpointConvertTo3wa)) + len(srv.RequestsTo(v3.EndpointAutoSuggest)); n != 0 {
+		t.Fatalf("ERROR: Expected unavailable languages to be rejected before a request, got %d requests", n)
+	}
+	if _, err := api.AutoSuggest(ctx, "index.home.ra", &v3.AutoSuggestOpts{Language: "oo", Locale: "oo_cy"}); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+
+	unvalidated := api.Clone(v3.WithoutValidation())
+	if _, err := unvalidated.AutoSuggest(ctx, "index.home.ra", &v3.AutoSuggestOpts{Language: "oo", Locale: "oo_xx"}); !errors.Is(err, v3.ErrBadLocale) {
+		t.Fatalf("ERROR: Expected the API to report ErrBadLocale, got %v", err)
+	}
+}
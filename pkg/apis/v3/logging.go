@@ -0,0 +1,193 @@
+package v3
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+// redacted replaces the value of sensitive headers in log records.
+const redacted = "REDACTED"
+
+// coordinateParams are the query parameters containing coordinates,
+// rounded when a coordinate precision is configured.
+var coordinateParams = []string{
+	"coordinates",
+	"focus",
+	"bounding-box",
+	"clip-to-bounding-box",
+	"clip-to-circle",
+	"clip-to-polygon",
+}
+
+var regexDecimal = regexp.MustCompile(`-?\d+\.\d+`)
+
+type logConfig struct {
+	logger       *slog.Logger
+	successLevel slog.Level
+	failureLevel slog.Level
+	// redactedHeaders holds canonical names of headers never logged,
+	// in addition to the API key header.
+	redactedHeaders []string
+	// coordinatePrecision is the number of decimals coordinates are
+	// rounded to, negative to log them unchanged.
+	coordinatePrecision int
+}
+
+func defaultLogConfig() logConfig {
+	return logConfig{
+		successLevel:        slog.LevelInfo,
+		failureLevel:        slog.LevelError,
+		coordinatePrecision: -1,
+	}
+}
+
+// WithLogger logs one record per request made by the API using the given
+// logger. Each record holds the endpoint, query parameters, request headers,
+// status code, duration, number of attempts, request ID and, on failure, the
+// error and its code. The API key header is always redacted. Calls answered
+// from a cache are logged with `cached` set, and calls rejected by validation
+// before a request is sent with `validation_failed` set.
+//
+// Example usage:
+//
+//	api := NewAPI("your-api-key", WithLogger(slog.Default()))
+func WithLogger(logger *slog.Logger) APIOption {
+	return func(vs *config) {
+		vs.log.logger = logger
+	}
+}
+
+// WithLogLevels sets the level of the records logged for successful and
+// failed requests. Defaults to slog.LevelInfo and slog.LevelError.
+//
+// Example usage:
+//
+//	api := NewAPI("your-api-key", WithLogger(logger), WithLogLevels(slog.LevelDebug, slog.LevelWarn))
+func WithLogLevels(success, failure slog.Level) APIOption {
+	return func(vs *config) {
+		vs.log.successLevel = success
+		vs.log.failureLevel = failure
+	}
+}
+
+// WithRedactedHeaders prevents the values of the given request headers from
+// being logged, for example headers holding tokens set with WithCustomHeader.
+// The API key header is always redacted.
+//
+// Example usage:
+//
+//	api := NewAPI("your-api-key", WithLogger(logger), WithRedactedHeaders("Authorization"))
+func WithRedactedHeaders(headers ...string) APIOption {
+	return func(vs *config) {
+		for _, header := range headers {
+			vs.log.redactedHeaders = append(vs.log.redactedHeaders, http.CanonicalHeaderKey(header))
+		}
+	}
+}
+
+// WithLoggedCoordinatePrecision rounds the coordinates logged in query
+// parameters, such as `coordinates`, `focus` and the clipping options,
+// to the given number of decimals. For example 2 decimals keep a precision
+// of roughly 1km, hiding the exact location of users.
+//
+// Example usage:
+//
+//	api := NewAPI("your-api-key", WithLogger(logger), WithLoggedCoordinatePrecision(2))
+func WithLoggedCoordinatePrecision(decimals int) APIOption {
+	return func(vs *config) {
+		vs.log.coordinatePrecision = max(decimals, 0)
+	}
+}
+
+// logCall logs the outcome of a request made to the endpoint.
+func (lc logConfig) logCall(ctx context.Context, endpoint string, queryParams, headers map[string]string, meta *ResponseMeta, err error) {
+	if lc.logger == nil {
+		return
+	}
+	level := lc.successLevel
+	if err != nil {
+		level = lc.failureLevel
+	}
+	if !lc.logger.Enabled(ctx, level) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("endpoint", endpoint),
+		slog.Group("query", lc.queryAttrs(queryParams)...),
+		slog.Group("headers", lc.headerAttrs(headers)...),
+		slog.Int("status", meta.StatusCode),
+		slog.Duration("duration", meta.Duration),
+		slog.Int("attempts", meta.Attempts),
+	}
+	if meta.Cached {
+		attrs = append(attrs, slog.Bool("cached", true))
+	}
+	if meta.Shared {
+		attrs = append(attrs, slog.Bool("shared", true))
+	}
+	if meta.RequestID != "" {
+		attrs = append(attrs, slog.String("request_id", meta.RequestID))
+	}
+	if err != nil {
+		var errResp *ErrorResponse
+		var ve *ValidationError
+		if errors.As(err, &errResp) {
+			attrs = append(attrs, slog.String("error_code", string(errResp.Code)))
+		}
+		if errors.As(err, &ve) {
+			// The call was rejected before a request was sent.
+			attrs = append(attrs, slog.Bool("validation_failed", true))
+		}
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	lc.logger.LogAttrs(ctx, level, "w3w api request", attrs...)
+}
+
+func (lc logConfig) queryAttrs(queryParams map[string]string) []any {
+	keys := make([]string, 0, len(queryParams))
+	for key := range queryParams {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	attrs := make([]any, 0, len(keys))
+	for _, key := range keys {
+		value := queryParams[key]
+		if lc.coordinatePrecision >= 0 && slices.Contains(coordinateParams, key) {
+			value = regexDecimal.ReplaceAllStringFunc(value, func(decimal string) string {
+				v, err := strconv.ParseFloat(decimal, 64)
+				if err != nil {
+					return decimal
+				}
+				return strconv.FormatFloat(v, 'f', lc.coordinatePrecision, 64)
+			})
+		}
+		attrs = append(attrs, slog.String(key, value))
+	}
+	return attrs
+}
+
+func (lc logConfig) headerAttrs(headers map[string]string) []any {
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	attrs := make([]any, 0, len(keys))
+	for _, key := range keys {
+		value := headers[key]
+		canonical := http.CanonicalHeaderKey(key)
+		if canonical == http.CanonicalHeaderKey(core.HEADER_API_KEY) || slices.Contains(lc.redactedHeaders, canonical) {
+			value = redacted
+		}
+		attrs = append(attrs, slog.String(key, value))
+	}
+	return attrs
+}
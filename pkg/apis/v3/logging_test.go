@@ -0,0 +1,111 @@
+package v3_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+func TestWithLogger(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-1")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"code":"BadCoordinates","message":"invalid coordinates"}}`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	api := v3.NewAPI("secret-key",
+		v3.WithCustomBaseURL(srv.URL),
+		v3.WithCustomHeader("Authorization", "Bearer token"),
+		v3.WithLogger(logger),
+		v3.WithLogLevels(slog.LevelDebug, slog.LevelWarn),
+		v3.WithRedactedHeaders("authorization"),
+		v3.WithLoggedCoordinatePrecision(2),
+	)
+	api.ConvertTo3wa(context.Background(), core.Coordinates{Lat: 51.520847, Lng: -0.195521}, &v3.ConvertAPIOpts{Language: "en"})
+
+	if bytes.Contains(buf.Bytes(), []byte("secret-key")) || bytes.Contains(buf.Bytes(), []byte("Bearer token")) {
+		t.Fatalf("ERROR: Sensitive header logged: %s", buf.String())
+	}
+	var record struct {
+		Level     string            `json:"level"`
+		Endpoint  string            `json:"endpoint"`
+		Query     map[string]string `json:"query"`
+		Headers   map[string]string `json:"headers"`
+		Status    int               `json:"status"`
+		Attempts  int               `json:"attempts"`
+		RequestID string            `json:"request_id"`
+		ErrorCode string            `json:"error_code"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("ERROR: Expected a single JSON record, got %s", buf.String())
+	}
+	if record.Level != "WARN" || record.Endpoint != v3.EndpointConvertTo3wa || record.Status != 400 || record.Attempts != 1 {
+		t.Fatalf("ERROR: Unexpected record %+v", record)
+	}
+	if record.RequestID != "req-1" || record.ErrorCode != "BadCoordinates" {
+		t.Fatalf("ERROR: Unexpected record %+v", record)
+	}
+	if record.Query["coordinates"] != "51.52,-0.20" || record.Query["language"] != "en" {
+		t.Fatalf("ERROR: Unexpected query %v", record.Query)
+	}
+	if record.Headers[core.HEADER_API_KEY] != "REDACTED" || record.Headers["Authorization"] != "REDACTED" {
+		t.Fatalf("ERROR: Expected headers to be redacted, got %v", record.Headers)
+	}
+}
+
+func TestWithLoggerUnsentCalls(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(c2cJson))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	api := v3.NewAPI("secret-key", v3.WithCustomBaseURL(srv.URL), v3.WithLogger(logger), v3.WithSquareCache(v3.NewSquareCache(100, 0)))
+	ctx := context.Background()
+	coordinates := core.Coordinates{Lat: 51.520847, Lng: -0.195521}
+	api.ConvertTo3wa(ctx, coordinates, nil)
+	api.ConvertTo3wa(ctx, coordinates, nil)
+	api.ConvertTo3wa(ctx, core.Coordinates{Lat: 100, Lng: -0.195521}, nil)
+
+	type record struct {
+		Level            string            `json:"level"`
+		Endpoint         string            `json:"endpoint"`
+		Query            map[string]string `json:"query"`
+		Headers          map[string]string `json:"headers"`
+		Status           int               `json:"status"`
+		Cached           bool              `json:"cached"`
+		ValidationFailed bool              `json:"validation_failed"`
+	}
+	var records []record
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var r record
+		if err := dec.Decode(&r); err != nil {
+			t.Fatalf("ERROR: Got error %v", err)
+		}
+		records = append(records, r)
+	}
+	if len(records) != 3 {
+		t.Fatalf("ERROR: Expected one record per call, got %+v", records)
+	}
+	if hit := records[1]; !hit.Cached || hit.Status != http.StatusOK || hit.Endpoint != v3.EndpointConvertTo3wa || hit.Query["coordinates"] != "51.520847,-0.195521" {
+		t.Fatalf("ERROR: Expected the record of a cache hit, got %+v", hit)
+	}
+	if invalid := records[2]; !invalid.ValidationFailed || invalid.Level != "ERROR" || invalid.Status != 0 || invalid.Headers[core.HEADER_API_KEY] != "REDACTED" {
+		t.Fatalf("ERROR: Expected the record of a call failing validation, got %+v", invalid)
+	}
+	if records[0].Cached || records[0].ValidationFailed {
+		t.Fatalf("ERROR: Expected the record of a request, got %+v", records[0])
+	}
+}
@@ -0,0 +1,65 @@
+package v3
+
+import (
+	"context"
+
+	"github.com/what3words/w3w-go-wrapper/internal/client"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+// HttpClient is the interface used by the API to send requests,
+// satisfied by *http.Client.
+type HttpClient = client.HttpClient
+
+// HttpClientFunc is an adapter allowing the use of an ordinary function
+// as an HttpClient, useful when writing a Middleware.
+type HttpClientFunc = client.HttpClientFunc
+
+// Middleware wraps the HttpClient used to send requests, allowing concerns
+// such as authentication, logging, tracing or fault injection to be stacked
+// around every request. Middlewares are invoked for every attempt made to
+// send a request, including retries.
+//
+// The logical name of the endpoint being called, such as `convert-to-3wa`,
+// can be retrieved from the request context using EndpointFromContext.
+//
+// Example usage:
+//
+//	logging := func(next HttpClient) HttpClient {
+//		return HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+//			log.Printf("calling %s", EndpointFromContext(req.Context()))
+//			return next.Do(req)
+//		})
+//	}
+//	api := NewAPI("your-api-key", WithMiddleware(logging))
+type Middleware func(next HttpClient) HttpClient
+
+// EndpointFromContext returns the logical name of the endpoint a request is
+// made to, such as EndpointConvertTo3wa, from the context of the request.
+func EndpointFromContext(ctx context.Context) string {
+	return core.EndpointFromContext(ctx)
+}
+
+// WithMiddleware adds middlewares wrapping the HTTP client of the API.
+// Middlewares are composed in the order they are added: the first one is
+// the outermost and sees each request first, the last one is the closest
+// to the HTTP client. Calling WithMiddleware multiple times appends to the
+// existing middlewares.
+//
+// Example usage:
+//
+//	api := NewAPI("your-api-key", WithMiddleware(auth, logging, tracing))
+func WithMiddleware(mw ...Middleware) APIOption {
+	return func(vs *config) {
+		vs.middleware = append(vs.middleware, mw...)
+	}
+}
+
+// chain wraps the client with the middlewares, the first middleware
+// being the outermost.
+func chain(c HttpClient, mw []Middleware) HttpClient {
+	for i := len(mw) - 1; i >= 0; i-- {
+		c = mw[i](c)
+	}
+	return c
+}
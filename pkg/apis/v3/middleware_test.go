@@ -0,0 +1,58 @@
+package v3_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+)
+
+func recordingMiddleware(name string, calls *[]string) v3.Middleware {
+	return func(next v3.HttpClient) v3.HttpClient {
+		return v3.HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			*calls = append(*calls, name+":"+v3.EndpointFromContext(req.Context()))
+			return next.Do(req)
+		})
+	}
+}
+
+func TestWithMiddlewareOrder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-injected") != "yes" {
+			t.Errorf("ERROR: Expected header injected by middleware")
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var calls []string
+	inject := func(next v3.HttpClient) v3.HttpClient {
+		return v3.HttpClientFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("x-injected", "yes")
+			return next.Do(req)
+		})
+	}
+	api := v3.NewAPI("key",
+		v3.WithCustomBaseURL(srv.URL),
+		v3.WithMiddleware(recordingMiddleware("first", &calls), recordingMiddleware("second", &calls)),
+		v3.WithMiddleware(inject),
+	)
+	api.AddMiddleware(recordingMiddleware("third", &calls))
+
+	if _, err := api.AutoSuggest(context.Background(), "filled.count.so", nil); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if _, err := api.AvailableLanguages(context.Background()); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	expected := []string{
+		"first:autosuggest", "second:autosuggest", "third:autosuggest",
+		"first:available-languages", "second:available-languages", "third:available-languages",
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("ERROR: Expected middleware calls %v, got %v", expected, calls)
+	}
+}
@@ -0,0 +1,31 @@
+package v3
+
+import "github.com/what3words/w3w-go-wrapper/pkg/core"
+
+// WithEndpointPool routes requests across the endpoints of the pool instead of
+// the base URL of the API, failing over to the next healthy endpoint when an
+// attempt fails with a transport error or a 5xx status code. Unlike
+// WithCustomBaseURL, the URL of each endpoint is used as given, so it must
+// include the path prefix the API is served under, and each endpoint can have
+// its own API key. Calls made with WithCallBaseURL are not routed by the pool.
+//
+// Example usage:
+//
+//	pool, err := core.NewEndpointPool([]core.Endpoint{
+//	    {URL: "https://w3w-1.internal.example.com/v3"},
+//	    {URL: "https://w3w-2.internal.example.com/w3w/v3"},
+//	    {URL: "https://api.what3words.com/v3", APIKey: "public-api-key"},
+//	}, core.EndpointPoolSettings{
+//	    Strategy:  core.RoutePriority,
+//	    ProbePath: EndpointAvailableLanguages,
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	defer pool.Close()
+//	api := NewAPI("enterprise-api-key", WithEndpointPool(pool))
+func WithEndpointPool(pool *core.EndpointPool) APIOption {
+	return func(vs *config) {
+		vs.pool = pool
+	}
+}
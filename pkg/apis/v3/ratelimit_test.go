@@ -0,0 +1,49 @@
+package v3_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+)
+
+func TestRateLimitSharedAcrossEndpoints(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	api := v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL), v3.WithRateLimit(0.1, 1))
+
+	if _, err := api.AvailableLanguages(context.Background()); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := api.AutoSuggest(ctx, "filled.count.so", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ERROR: Expected request to be rate limited, got %v", err)
+	}
+}
+
+func TestEndpointRateLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	api := v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL), v3.WithEndpointRateLimit(0.1, 1, v3.EndpointAutoSuggest))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := api.AutoSuggest(ctx, "filled.count.so", nil); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if _, err := api.AvailableLanguages(ctx); err != nil {
+		t.Fatalf("ERROR: Expected endpoint without sub-limit to pass, got %v", err)
+	}
+	if _, err := api.AutoSuggest(ctx, "filled.count.so", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ERROR: Expected request to be rate limited, got %v", err)
+	}
+}
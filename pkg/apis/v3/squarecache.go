@@ -0,0 +1,202 @@
+package v3
+
+import (
+	"container/list"
+	"math"
+	"sync"
+	"time"
+)
+
+// squareBucketSize is the size, in degrees, of the grid cells used to index
+// squares. It is much larger than a 3m square so that a square overlaps at
+// most 4 cells, while keeping the number of squares per cell small.
+const squareBucketSize = 0.001
+
+type squareBucket struct {
+	lat, lng int64
+}
+
+func bucketOf(c Coordinates) squareBucket {
+	return squareBucket{
+		lat: int64(math.Floor(c.Lat / squareBucketSize)),
+		lng: int64(math.Floor(c.Lng / squareBucketSize)),
+	}
+}
+
+type squareEntry struct {
+	variant string
+	resp    ConvertAPIJsonResponse
+	expires time.Time
+	buckets []squareBucket
+}
+
+func (se *squareEntry) contains(c Coordinates) bool {
+	sq := se.resp.Square
+	return c.Lat >= sq.SouthWest.Lat && c.Lat < sq.NorthEast.Lat &&
+		c.Lng >= sq.SouthWest.Lng && c.Lng < sq.NorthEast.Lng
+}
+
+// SquareCache answers ConvertTo3wa locally for any coordinates falling inside
+// a square previously returned by the API. Unlike a Cache keyed by query
+// parameters, coordinates which differ slightly but fall in the same 3m square,
+// such as repeated GPS fixes of a vehicle, are all served from a single entry.
+//
+// Squares are indexed in a grid of cells of roughly 100m, and the cache is
+// bounded by its number of squares, evicting the least recently used one when
+// full. Squares are stored separately per language and locale. Squares stored
+// by an API are also kept apart per base URL, or endpoint of its pool, and API
+// key, so that APIs sharing the cache never answer from the squares of another
+// backend or key. It is safe for concurrent use and can be shared by several
+// APIs.
+type SquareCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ttl        time.Duration
+	order      *list.List
+	buckets    map[squareBucket][]*list.Element
+}
+
+// NewSquareCache creates a SquareCache holding at most maxEntries squares,
+// each expiring after ttl. A ttl of zero or less means squares never expire.
+// A maxEntries below 1 is treated as 1.
+func NewSquareCache(maxEntries int, ttl time.Duration) *SquareCache {
+	return &SquareCache{
+		maxEntries: max(maxEntries, 1),
+		ttl:        ttl,
+		order:      list.New(),
+		buckets:    make(map[squareBucket][]*list.Element),
+	}
+}
+
+// WithSquareCache answers ConvertTo3wa calls from the given SquareCache
+// whenever the coordinates fall inside a square already returned by the
+// ConvertTo3wa or ConvertToCoordinates endpoints in the JSON format.
+// Calls answered by the SquareCache send no request and report Cached
+// in their ResponseMeta. Squares are kept per base URL, or endpoint of
+// the pool, and API key, so the SquareCache can be shared by clones and
+// APIs of other backends or keys. Calls with WithCallBaseURL,
+// WithCallAPIKey or WithCallHeader neither use nor fill the SquareCache.
+//
+// Example usage:
+//
+//	api := NewAPI("your-api-key", WithSquareCache(NewSquareCache(100000, time.Hour)))
+func WithSquareCache(sc *SquareCache) APIOption {
+	return func(vs *config) {
+		vs.squareCache = sc
+	}
+}
+
+// squareVariant identifies the squares of the target, see core.Target, in
+// the language and locale of opts.
+func squareVariant(target string, opts *ConvertAPIOpts) string {
+	if opts == nil {
+		return target
+	}
+	return target + "|" + opts.Language + "|" + opts.Locale
+}
+
+// Lookup returns the response of the square containing the coordinates,
+// for the language and locale of opts, if one was cached with Store.
+func (sc *SquareCache) Lookup(coordinates Coordinates, opts *ConvertAPIOpts) (*ConvertAPIJsonResponse, bool) {
+	return sc.lookup("", coordinates, opts)
+}
+
+// lookup returns the response of the square containing the coordinates
+// stored for the target.
+func (sc *SquareCache) lookup(target string, coordinates Coordinates, opts *ConvertAPIOpts) (*ConvertAPIJsonResponse, bool) {
+	variant := squareVariant(target, opts)
+	now := time.Now()
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	for _, elem := range sc.buckets[bucketOf(coordinates)] {
+		entry := elem.Value.(*squareEntry)
+		if entry.variant != variant || !entry.contains(coordinates) {
+			continue
+		}
+		if !entry.expires.IsZero() && now.After(entry.expires) {
+			sc.remove(elem)
+			return nil, false
+		}
+		sc.order.MoveToFront(elem)
+		resp := entry.resp
+		return &resp, true
+	}
+	return nil, false
+}
+
+// Store caches the response for the language and locale of opts. Responses
+// with an empty square, or a square crossing the anti-meridian, are ignored.
+// Squares stored by APIs are not returned by Lookup.
+func (sc *SquareCache) Store(resp *ConvertAPIJsonResponse, opts *ConvertAPIOpts) {
+	sc.store("", resp, opts)
+}
+
+// store caches the response received from the target.
+func (sc *SquareCache) store(target string, resp *ConvertAPIJsonResponse, opts *ConvertAPIOpts) {
+	if resp == nil {
+		return
+	}
+	sq := resp.Square
+	if sq.SouthWest.Lat >= sq.NorthEast.Lat || sq.SouthWest.Lng >= sq.NorthEast.Lng {
+		return
+	}
+	entry := &squareEntry{
+		variant: squareVariant(target, opts),
+		resp:    *resp,
+	}
+	if sc.ttl > 0 {
+		entry.expires = time.Now().Add(sc.ttl)
+	}
+	sw, ne := bucketOf(sq.SouthWest), bucketOf(sq.NorthEast)
+	for lat := sw.lat; lat <= ne.lat; lat++ {
+		for lng := sw.lng; lng <= ne.lng; lng++ {
+			entry.buckets = append(entry.buckets, squareBucket{lat, lng})
+		}
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	centre := Coordinates{
+		Lat: (sq.SouthWest.Lat + sq.NorthEast.Lat) / 2,
+		Lng: (sq.SouthWest.Lng + sq.NorthEast.Lng) / 2,
+	}
+	for _, elem := range sc.buckets[bucketOf(centre)] {
+		if existing := elem.Value.(*squareEntry); existing.variant == entry.variant && existing.resp.Square == sq {
+			sc.remove(elem)
+			break
+		}
+	}
+	elem := sc.order.PushFront(entry)
+	for _, bucket := range entry.buckets {
+		sc.buckets[bucket] = append(sc.buckets[bucket], elem)
+	}
+	for sc.order.Len() > sc.maxEntries {
+		sc.remove(sc.order.Back())
+	}
+}
+
+// Len returns the number of squares in the cache, including expired
+// squares not yet evicted.
+func (sc *SquareCache) Len() int {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.order.Len()
+}
+
+func (sc *SquareCache) remove(elem *list.Element) {
+	sc.order.Remove(elem)
+	for _, bucket := range elem.Value.(*squareEntry).buckets {
+		elems := sc.buckets[bucket]
+		for i, e := range elems {
+			if e == elem {
+				elems = append(elems[:i], elems[i+1:]...)
+				break
+			}
+		}
+		if len(elems) == 0 {
+			delete(sc.buckets, bucket)
+		} else {
+			sc.buckets[bucket] = elems
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package v3_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+func TestWithSquareCache(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(c2cJson))
+	}))
+	defer srv.Close()
+
+	api := v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL), v3.WithSquareCache(v3.NewSquareCache(100, 0)))
+	fixes := []core.Coordinates{
+		{Lat: 51.520847, Lng: -0.195521},
+		{Lat: 51.520834, Lng: -0.195542},
+		{Lat: 51.520859, Lng: -0.195500},
+	}
+	var first v3.ResponseMeta
+	for i, fix := range fixes {
+		var meta v3.ResponseMeta
+		resp, err := api.ConvertTo3wa(context.Background(), fix, nil, v3.WithResponseMetadata(&meta))
+		if err != nil {
+			t.Fatalf("ERROR: Got error %v", err)
+		}
+		if resp.Words != "filled.count.soap" {
+			t.Fatalf("ERROR: Expected filled.count.soap, got %s", resp.Words)
+		}
+		if i == 0 {
+			first = meta
+		} else if !meta.Cached || meta.Endpoint != first.Endpoint || meta.StatusCode != http.StatusOK {
+			t.Fatalf("ERROR: Expected the metadata of a cached response for %s, got %+v", first.Endpoint, meta)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("ERROR: Expected fixes inside the square to hit the cache, got %d requests", calls.Load())
+	}
+	if _, err := api.ConvertTo3wa(context.Background(), fixes[0], &v3.ConvertAPIOpts{Language: "eng"}); err == nil {
+		t.Fatal("ERROR: Expected invalid options to be rejected before looking up the cache")
+	}
+
+	api.ConvertTo3wa(context.Background(), core.Coordinates{Lat: 51.520861, Lng: -0.195521}, nil)
+	if calls.Load() != 2 {
+		t.Fatalf("ERROR: Expected coordinates outside the square to miss the cache, got %d requests", calls.Load())
+	}
+	api.ConvertTo3wa(context.Background(), fixes[0], &v3.ConvertAPIOpts{Language: "de"})
+	if calls.Load() != 3 {
+		t.Fatalf("ERROR: Expected a different language to miss the cache, got %d requests", calls.Load())
+	}
+	api.ConvertTo3wa(context.Background(), fixes[0], nil, v3.WithCallAPIKey("other-key"))
+	api.ConvertTo3wa(context.Background(), fixes[0], nil, v3.WithCallBaseURL(srv.URL))
+	if calls.Load() != 5 {
+		t.Fatalf("ERROR: Expected calls overriding the API key or base URL to bypass the cache, got %d requests", calls.Load())
+	}
+}
+
+func TestSquareCacheSharedAcrossTargets(t *testing.T) {
+	var calls [2]atomic.Int32
+	var servers [2]*httptest.Server
+	for i := range servers {
+		i := i
+		servers[i] = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls[i].Add(1)
+			w.Write([]byte(c2cJson))
+		}))
+		defer servers[i].Close()
+	}
+
+	sc := v3.NewSquareCache(100, 0)
+	api := v3.NewAPI("key", v3.WithCustomBaseURL(servers[0].URL), v3.WithSquareCache(sc))
+	fix := core.Coordinates{Lat: 51.520847, Lng: -0.195521}
+	api.ConvertTo3wa(context.Background(), fix, nil)
+	api.ConvertTo3wa(context.Background(), fix, nil)
+	if calls[0].Load() != 1 {
+		t.Fatalf("ERROR: Expected the second call to hit the cache, got %d requests", calls[0].Load())
+	}
+
+	var meta v3.ResponseMeta
+	clone := api.Clone(v3.WithCustomBaseURL(servers[1].URL))
+	clone.ConvertTo3wa(context.Background(), fix, nil, v3.WithResponseMetadata(&meta))
+	if calls[1].Load() != 1 || meta.Cached {
+		t.Fatalf("ERROR: Expected a clone with another base URL to miss the squares of the first, got %d requests", calls[1].Load())
+	}
+	clone.ConvertTo3wa(context.Background(), fix, nil, v3.WithResponseMetadata(&meta))
+	if calls[1].Load() != 1 || !meta.Cached {
+		t.Fatalf("ERROR: Expected the clone to hit its own squares, got %d requests", calls[1].Load())
+	}
+
+	other := v3.NewAPI("other-key", v3.WithCustomBaseURL(servers[0].URL), v3.WithSquareCache(sc))
+	other.ConvertTo3wa(context.Background(), fix, nil)
+	if calls[0].Load() != 2 {
+		t.Fatalf("ERROR: Expected an API with another key to miss the squares of the first, got %d requests", calls[0].Load())
+	}
+
+	sc.Store(&v3.ConvertAPIJsonResponse{Words: "pretty.needed.chill", Square: v3.Sqaure{
+		SouthWest: core.Coordinates{Lat: 51.751159, Lng: -1.246252},
+		NorthEast: core.Coordinates{Lat: 51.751186, Lng: -1.246208},
+	}}, nil)
+	api.ConvertTo3wa(context.Background(), core.Coordinates{Lat: 51.75117, Lng: -1.24623}, nil)
+	if calls[0].Load() != 3 {
+		t.Fatalf("ERROR: Expected squares stored with Store not to answer API calls, got %d requests", calls[0].Load())
+	}
+	if _, ok := sc.Lookup(fix, nil); ok {
+		t.Fatal("ERROR: Expected squares stored by APIs not to be returned by Lookup")
+	}
+}
+
+func TestSquareCacheFromConvertToCoordinatesAndEviction(t *testing.T) {
+	sc := v3.NewSquareCache(1, 0)
+	var resp v3.ConvertAPIJsonResponse
+	resp.Words = "filled.count.soap"
+	resp.Square = v3.Sqaure{
+		SouthWest: core.Coordinates{Lat: 51.520833, Lng: -0.195543},
+		NorthEast: core.Coordinates{Lat: 51.52086, Lng: -0.195499},
+	}
+	sc.Store(&resp, nil)
+	if got, ok := sc.Lookup(core.Coordinates{Lat: 51.52085, Lng: -0.1955}, nil); !ok || got.Words != resp.Words {
+		t.Fatalf("ERROR: Expected a hit, got %v %v", got, ok)
+	}
+	other := resp
+	other.Words = "pretty.needed.chill"
+	other.Square = v3.Sqaure{
+		SouthWest: core.Coordinates{Lat: 51.751159, Lng: -1.246252},
+		NorthEast: core.Coordinates{Lat: 51.751186, Lng: -1.246208},
+	}
+	sc.Store(&other, nil)
+	if sc.Len() != 1 {
+		t.Fatalf("ERROR: Expected cache to be bounded to 1 square, got %d", sc.Len())
+	}
+	if _, ok := sc.Lookup(core.Coordinates{Lat: 51.52085, Lng: -0.1955}, nil); ok {
+		t.Fatal("ERROR: Expected least recently used square to be evicted")
+	}
+}
@@ -39,11 +39,11 @@ func (cto ConvertAPIOpts) asOptionsMap() map[string]string {
 // returned by the what3words public api convert endpoints
 type ConvertAPIGeoJsonResponse struct {
 	Features []struct {
-		Bbox     []float64 `json:"Bbox"`
+		Bbox     []float64 `json:"bbox,omitempty"`
 		Geometry struct {
 			Coordinates  []float64 `json:"coordinates"`
 			GeometryType string    `json:"type"`
-		}
+		} `json:"geometry"`
 		FeatureType string `json:"type"`
 		Properties  struct {
 			Country      string `json:"country"`
@@ -66,6 +66,7 @@ type ConvertAPIJsonResponse struct {
 	Coordinates  Coordinates `json:"coordinates,omitempty"`
 	Words        string      `json:"words,omitempty"`
 	Language     string      `json:"language,omitempty"`
+	Locale       string      `json:"locale,omitempty"`
 	MapUrl       string      `json:"map,omitempty"`
 }
 
@@ -76,7 +77,7 @@ type convertAPIResponse struct {
 }
 
 func (ctr convertAPIResponse) GetError() error {
-	return ctr.Error
+	return ctr.Error.asError()
 }
 
 // AutoSuggest API
@@ -103,6 +104,64 @@ func (bb BoundingBox) asQueryParam() string {
 
 type Polygon []Coordinates
 
+// InputType is the type of the input passed to AutoSuggest. Voice types
+// take the output of a speech recognition engine, either the recognised
+// words separated by spaces or the payload of the engine, such as the JSON
+// output of VoCon Hybrid or Nuance Mix, as is.
+type InputType string
+
+const (
+	// InputTypeText is typed text, the default.
+	InputTypeText InputType = "text"
+	// InputTypeVoconHybrid is the JSON output of the Cerence VoCon Hybrid engine.
+	InputTypeVoconHybrid InputType = "vocon-hybrid"
+	// InputTypeNMDPASR is the output of the Nuance NMDP ASR engine.
+	InputTypeNMDPASR InputType = "nmdp-asr"
+	// InputTypeGenericVoice is the words recognised by any speech
+	// recognition engine, separated by spaces.
+	InputTypeGenericVoice InputType = "generic-voice"
+	// InputTypeSpeechmatics is the output of the Speechmatics engine.
+	InputTypeSpeechmatics InputType = "speechmatics"
+	// InputTypeMihup is the output of the Mihup engine.
+	InputTypeMihup InputType = "mihup"
+	// InputTypeMawdoo3 is the output of the Mawdoo3 engine.
+	InputTypeMawdoo3 InputType = "mawdoo3"
+	// InputTypeOCRAbbyy is the output of the ABBYY optical character
+	// recognition engine.
+	InputTypeOCRAbbyy InputType = "ocr-abbyy"
+)
+
+// InputTypes lists the input types accepted by AutoSuggest.
+var InputTypes = []InputType{
+	InputTypeText,
+	InputTypeVoconHybrid,
+	InputTypeNMDPASR,
+	InputTypeGenericVoice,
+	InputTypeSpeechmatics,
+	InputTypeMihup,
+	InputTypeMawdoo3,
+	InputTypeOCRAbbyy,
+}
+
+// IsVoice reports whether the input type is the output of a speech
+// recognition engine, for which AutoSuggest requires a Language.
+func (it InputType) IsVoice() bool {
+	switch it {
+	case InputTypeVoconHybrid, InputTypeNMDPASR, InputTypeGenericVoice, InputTypeSpeechmatics, InputTypeMihup, InputTypeMawdoo3:
+		return true
+	}
+	return false
+}
+
+// sourceAPI returns the source API of suggestions for inputs of this type,
+// as reported to AutoSuggestSelection.
+func (it InputType) sourceAPI() string {
+	if it.IsVoice() {
+		return SourceAPIVoice
+	}
+	return SourceAPIText
+}
+
 // AutoSuggestOpts models all the possible
 // optional options availabel for /v3/autosuggest
 // api
@@ -137,7 +196,11 @@ type AutoSuggestOpts struct {
 	// For normal text input, used to specify a fallback language which will help guide AutoSuggest if the input
 	// is particularly messy. If specified, this parameter must be a supported 3 word address language
 	// as an ISO 639-1 2 letter code. For Bosnian-Croatian-Montenegrin-Serbian use oo.
+	// Required for voice input types.
 	Language string
+	// Type of the input, text by default. Voice input types, such as
+	// InputTypeGenericVoice, require Language to be set.
+	InputType InputType
 	// Makes AutoSuggest prefer results on land to those in the sea. This setting is on by default.
 	// Use false to disable this setting and receive more suggestions in the sea.
 	// Set easily using `core.Bool(false)`
@@ -177,6 +240,9 @@ func (aso AutoSuggestOpts) asOptionsMap() map[string]string {
 	if aso.Language != "" {
 		mapOpts["language"] = aso.Language
 	}
+	if aso.InputType != "" {
+		mapOpts["input-type"] = string(aso.InputType)
+	}
 	if aso.PreferLand != nil {
 		mapOpts["prefer-land"] = strconv.FormatBool(*aso.PreferLand)
 	}
@@ -187,7 +253,7 @@ func (aso AutoSuggestOpts) asOptionsMap() map[string]string {
 		mapOpts["n-results"] = strconv.Itoa(*aso.NResults)
 	}
 	if aso.NFocusResult != nil {
-		mapOpts["n-focus-result"] = strconv.Itoa(*aso.NFocusResult)
+		mapOpts["n-focus-results"] = strconv.Itoa(*aso.NFocusResult)
 	}
 
 	return mapOpts
@@ -197,10 +263,10 @@ type AutoSuggestSuggestion struct {
 	Country           string `json:"country"`
 	NearestPlace      string `json:"nearestPlace"`
 	Words             string `json:"words"`
-	DistanceToFocusKm int    `json:"distanceToFocusKm"`
+	DistanceToFocusKm int    `json:"distanceToFocusKm,omitempty"`
 	Rank              int    `json:"rank"`
 	Language          string `json:"language"`
-	Locale            string `json:"locale"`
+	Locale            string `json:"locale,omitempty"`
 }
 
 // AutoSuggestGeoJsonResponse models the response recieved
@@ -209,6 +275,21 @@ type AutoSuggestResponse struct {
 	Suggestions []AutoSuggestSuggestion `json:"suggestions,omitempty"`
 }
 
+// Source APIs of the suggestions reported to AutoSuggestSelection, voice
+// for suggestions of voice input types and text otherwise.
+const (
+	SourceAPIText  = "text"
+	SourceAPIVoice = "voice"
+)
+
+type autoSuggestSelectionResponse struct {
+	Error *ErrorResponse `json:"error"`
+}
+
+func (assr autoSuggestSelectionResponse) GetError() error {
+	return assr.Error.asError()
+}
+
 type AutoSuggestWithCoordinatesSuggestion struct {
 	AutoSuggestSuggestion
 	Coordinates Coordinates `json:"coordinates"`
@@ -226,7 +307,7 @@ type autoSuggestResponse struct {
 }
 
 func (asr autoSuggestResponse) GetError() error {
-	return asr.Error
+	return asr.Error.asError()
 }
 
 type autoSuggestWithCoordinatesResponse struct {
@@ -235,7 +316,7 @@ type autoSuggestWithCoordinatesResponse struct {
 }
 
 func (asr autoSuggestWithCoordinatesResponse) GetError() error {
-	return asr.Error
+	return asr.Error.asError()
 }
 
 // Grid Section API
@@ -271,7 +352,7 @@ type gridSectionResponse struct {
 }
 
 func (gr gridSectionResponse) GetError() error {
-	return gr.Error
+	return gr.Error.asError()
 }
 
 // GridSectionResponse encapsulates 2 pointers to
@@ -288,6 +369,17 @@ type Language struct {
 	NativeName string `json:"nativeName"`
 	Code       string `json:"code"`
 	Name       string `json:"name"`
+	// Locales lists the variants of the language, such as the Cyrillic and
+	// Latin scripts of Mongolian, for languages which have several.
+	Locales []Locale `json:"locales,omitempty"`
+}
+
+// Locale models a variant of a language, its Code being the code of the
+// language followed by the variant, such as mn_cy.
+type Locale struct {
+	NativeName string `json:"nativeName"`
+	Code       string `json:"code"`
+	Name       string `json:"name"`
 }
 
 // AvailableLanguagesResponse models the response recieved
@@ -302,5 +394,5 @@ type availableLanguagesResponse struct {
 }
 
 func (alr availableLanguagesResponse) GetError() error {
-	return alr.Error
+	return alr.Error.asError()
 }
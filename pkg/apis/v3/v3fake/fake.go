@@ -0,0 +1,356 @@
+// Package v3fake provides FakeAPI, an in-memory implementation of v3.API
+// for tests.
+//
+// Responses are scripted per method and input, calls are recorded and can
+// be asserted on:
+//
+//	api := v3fake.New()
+//	api.OnConvertToCoordinates("filled.count.soap").Return(&v3.ConvertAPIJsonResponse{...})
+//	api.OnConvertToCoordinates().ReturnError(v3.ErrBadWords)
+//	api.SetLatency(v3fake.MethodAutoSuggest, 50*time.Millisecond)
+//
+//	// Exercise the code under test with api.
+//
+//	api.AssertCalled(t, v3fake.MethodConvertToCoordinates, "filled.count.soap")
+//
+// Calls to a method without scripted result return an error wrapping
+// ErrUnscripted.
+package v3fake
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/what3words/w3w-go-wrapper/internal/client"
+	"github.com/what3words/w3w-go-wrapper/internal/fake"
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+// Methods of v3.API that can be scripted and asserted on.
+const (
+	MethodConvertTo3wa                = "ConvertTo3wa"
+	MethodConvertTo3waGeoJson         = "ConvertTo3waGeoJson"
+	MethodConvertToCoordinates        = "ConvertToCoordinates"
+	MethodConvertToCoordinatesGeoJson = "ConvertToCoordinatesGeoJson"
+	MethodGridSection                 = "GridSection"
+	MethodGridSectionGeoJson          = "GridSectionGeoJson"
+	MethodAutoSuggest                 = "AutoSuggest"
+	MethodAutoSuggestWithCoordinates  = "AutoSuggestWithCoordinates"
+	MethodAutoSuggestSelection        = "AutoSuggestSelection"
+	MethodAvailableLanguages          = "AvailableLanguages"
+)
+
+// ErrUnscripted is returned by calls to a method without scripted result.
+var ErrUnscripted = fake.ErrUnscripted
+
+// Call is a call made to a FakeAPI. Input holds the coordinates, words,
+// input, Selection or bounding box passed to the method, and Opts the
+// *v3.ConvertAPIOpts or *v3.AutoSuggestOpts.
+type Call = fake.Call
+
+// Selection is the input of AutoSuggestSelection.
+type Selection struct {
+	RawInput   string
+	Suggestion v3.AutoSuggestSuggestion
+}
+
+// FakeAPI is an in-memory v3.API. It is safe for concurrent use.
+type FakeAPI struct {
+	reg *fake.Registry
+
+	mu          sync.Mutex
+	baseURL     string
+	headers     map[string]string
+	client      client.HttpClient
+	retryPolicy *core.RetryPolicy
+	middlewares []v3.Middleware
+}
+
+var _ v3.API = (*FakeAPI)(nil)
+
+// New returns a FakeAPI without scripted result.
+func New() *FakeAPI {
+	return &FakeAPI{
+		reg:     fake.NewRegistry(),
+		headers: make(map[string]string),
+	}
+}
+
+// Stub scripts the results of a method for some inputs. Results are returned
+// in the order they are added, the last one being repeated.
+type Stub[T any] struct {
+	reg    *fake.Registry
+	method string
+	inputs []any
+	delay  time.Duration
+}
+
+func newStub[T any, I any](reg *fake.Registry, method string, inputs []I) *Stub[T] {
+	s := &Stub[T]{reg: reg, method: method}
+	for _, input := range inputs {
+		s.inputs = append(s.inputs, input)
+	}
+	return s
+}
+
+// Return adds a successful result.
+func (s *Stub[T]) Return(resp T) *Stub[T] {
+	s.reg.Add(s.method, s.inputs, fake.Result{Value: resp, Delay: s.delay})
+	return s
+}
+
+// ReturnError adds a failed result.
+func (s *Stub[T]) ReturnError(err error) *Stub[T] {
+	s.reg.Add(s.method, s.inputs, fake.Result{Err: err, Delay: s.delay})
+	return s
+}
+
+// After delays the results added next by d.
+func (s *Stub[T]) After(d time.Duration) *Stub[T] {
+	s.delay = d
+	return s
+}
+
+// OnConvertTo3wa scripts ConvertTo3wa for the coordinates, or for any
+// coordinates when none is given.
+func (f *FakeAPI) OnConvertTo3wa(coordinates ...core.Coordinates) *Stub[*v3.ConvertAPIJsonResponse] {
+	return newStub[*v3.ConvertAPIJsonResponse](f.reg, MethodConvertTo3wa, coordinates)
+}
+
+// OnConvertTo3waGeoJson scripts ConvertTo3waGeoJson for the coordinates, or
+// for any coordinates when none is given.
+func (f *FakeAPI) OnConvertTo3waGeoJson(coordinates ...core.Coordinates) *Stub[*v3.ConvertAPIGeoJsonResponse] {
+	return newStub[*v3.ConvertAPIGeoJsonResponse](f.reg, MethodConvertTo3waGeoJson, coordinates)
+}
+
+// OnConvertToCoordinates scripts ConvertToCoordinates for the words, or for
+// any words when none is given.
+func (f *FakeAPI) OnConvertToCoordinates(words ...string) *Stub[*v3.ConvertAPIJsonResponse] {
+	return newStub[*v3.ConvertAPIJsonResponse](f.reg, MethodConvertToCoordinates, words)
+}
+
+// OnConvertToCoordinatesGeoJson scripts ConvertToCoordinatesGeoJson for the
+// words, or for any words when none is given.
+func (f *FakeAPI) OnConvertToCoordinatesGeoJson(words ...string) *Stub[*v3.ConvertAPIGeoJsonResponse] {
+	return newStub[*v3.ConvertAPIGeoJsonResponse](f.reg, MethodConvertToCoordinatesGeoJson, words)
+}
+
+// OnGridSection scripts GridSection for the bounding boxes, or for any
+// bounding box when none is given.
+func (f *FakeAPI) OnGridSection(boundingBoxes ...v3.BoundingBox) *Stub[*v3.GridSectionJsonResponse] {
+	return newStub[*v3.GridSectionJsonResponse](f.reg, MethodGridSection, boundingBoxes)
+}
+
+// OnGridSectionGeoJson scripts GridSectionGeoJson for the bounding boxes, or
+// for any bounding box when none is given.
+func (f *FakeAPI) OnGridSectionGeoJson(boundingBoxes ...v3.BoundingBox) *Stub[*v3.GridSectionGeoJsonResponse] {
+	return newStub[*v3.GridSectionGeoJsonResponse](f.reg, MethodGridSectionGeoJson, boundingBoxes)
+}
+
+// OnAutoSuggest scripts AutoSuggest for the inputs, or for any input when
+// none is given.
+func (f *FakeAPI) OnAutoSuggest(inputs ...string) *Stub[*v3.AutoSuggestResponse] {
+	return newStub[*v3.AutoSuggestResponse](f.reg, MethodAutoSuggest, inputs)
+}
+
+// OnAutoSuggestWithCoordinates scripts AutoSuggestWithCoordinates for the
+// inputs, or for any input when none is given.
+func (f *FakeAPI) OnAutoSuggestWithCoordinates(inputs ...string) *Stub[*v3.AutoSuggestWithCoordinatesResponse] {
+	return newStub[*v3.AutoSuggestWithCoordinatesResponse](f.reg, MethodAutoSuggestWithCoordinates, inputs)
+}
+
+// OnAutoSuggestSelection scripts AutoSuggestSelection for the selections, or
+// for any selection when none is given. Successful results are added with
+// Return(struct{}{}).
+func (f *FakeAPI) OnAutoSuggestSelection(selections ...Selection) *Stub[struct{}] {
+	return newStub[struct{}](f.reg, MethodAutoSuggestSelection, selections)
+}
+
+// OnAvailableLanguages scripts AvailableLanguages.
+func (f *FakeAPI) OnAvailableLanguages() *Stub[*v3.AvailableLanguagesResponse] {
+	return newStub[*v3.AvailableLanguagesResponse, any](f.reg, MethodAvailableLanguages, nil)
+}
+
+// SetLatency delays every call to the method by d, or every call to any
+// method when method is empty. The delay is cut short when the context of
+// the call is done.
+func (f *FakeAPI) SetLatency(method string, d time.Duration) {
+	f.reg.SetLatency(method, d)
+}
+
+// Reset forgets the scripted results, latencies and recorded calls.
+func (f *FakeAPI) Reset() {
+	f.reg.Reset()
+}
+
+// Calls returns the calls made so far, in order, to the given methods or
+// to any method when none is given.
+func (f *FakeAPI) Calls(methods ...string) []Call {
+	return f.reg.Calls(methods...)
+}
+
+// AssertCalled fails the test unless the method was called, with one of the
+// inputs when any is given.
+func (f *FakeAPI) AssertCalled(t testing.TB, method string, inputs ...any) {
+	t.Helper()
+	f.reg.AssertCalled(t, method, inputs...)
+}
+
+// AssertNotCalled fails the test if the method was called.
+func (f *FakeAPI) AssertNotCalled(t testing.TB, method string) {
+	t.Helper()
+	f.reg.AssertNotCalled(t, method)
+}
+
+// AssertCallCount fails the test unless the method was called n times.
+func (f *FakeAPI) AssertCallCount(t testing.TB, method string, n int) {
+	t.Helper()
+	f.reg.AssertCallCount(t, method, n)
+}
+
+// BaseURL returns the URL last passed to SetBaseURL.
+func (f *FakeAPI) BaseURL() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.baseURL
+}
+
+// Header returns the value of the header set with SetHeader or SetHeaderMap.
+func (f *FakeAPI) Header(key string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.headers[key]
+}
+
+// Client returns the client last passed to SetClient.
+func (f *FakeAPI) Client() client.HttpClient {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.client
+}
+
+// RetryPolicy returns the policy last passed to SetRetryPolicy.
+func (f *FakeAPI) RetryPolicy() *core.RetryPolicy {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.retryPolicy
+}
+
+// Middlewares returns the middlewares added with AddMiddleware. They are
+// not applied to calls.
+func (f *FakeAPI) Middlewares() []v3.Middleware {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]v3.Middleware(nil), f.middlewares...)
+}
+
+func (f *FakeAPI) SetBaseURL(baseURL string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.baseURL = baseURL
+}
+
+func (f *FakeAPI) SetHeader(headerKey, headerValue string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.headers[headerKey] = headerValue
+}
+
+func (f *FakeAPI) SetHeaderMap(headers map[string]string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.headers = make(map[string]string, len(headers))
+	for k, v := range headers {
+		f.headers[k] = v
+	}
+}
+
+func (f *FakeAPI) SetClient(client client.HttpClient) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.client = client
+}
+
+func (f *FakeAPI) SetRetryPolicy(policy *core.RetryPolicy) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.retryPolicy = policy
+}
+
+func (f *FakeAPI) AddMiddleware(mw ...v3.Middleware) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.middlewares = append(f.middlewares, mw...)
+}
+
+// Clone returns the FakeAPI itself so that calls made through clones are
+// scripted and recorded in one place. The options are ignored.
+func (f *FakeAPI) Clone(opts ...v3.APIOption) v3.API {
+	return f
+}
+
+func (f *FakeAPI) ConvertTo3wa(ctx context.Context, coordinates core.Coordinates, opts *v3.ConvertAPIOpts, callOpts ...v3.CallOption) (*v3.ConvertAPIJsonResponse, error) {
+	return call[*v3.ConvertAPIJsonResponse](ctx, f.reg, MethodConvertTo3wa, coordinates, opts)
+}
+
+func (f *FakeAPI) ConvertTo3waGeoJson(ctx context.Context, coordinates core.Coordinates, opts *v3.ConvertAPIOpts, callOpts ...v3.CallOption) (*v3.ConvertAPIGeoJsonResponse, error) {
+	return call[*v3.ConvertAPIGeoJsonResponse](ctx, f.reg, MethodConvertTo3waGeoJson, coordinates, opts)
+}
+
+func (f *FakeAPI) ConvertToCoordinates(ctx context.Context, words string, opts *v3.ConvertAPIOpts, callOpts ...v3.CallOption) (*v3.ConvertAPIJsonResponse, error) {
+	return call[*v3.ConvertAPIJsonResponse](ctx, f.reg, MethodConvertToCoordinates, words, opts)
+}
+
+func (f *FakeAPI) ConvertToCoordinatesGeoJson(ctx context.Context, words string, opts *v3.ConvertAPIOpts, callOpts ...v3.CallOption) (*v3.ConvertAPIGeoJsonResponse, error) {
+	return call[*v3.ConvertAPIGeoJsonResponse](ctx, f.reg, MethodConvertToCoordinatesGeoJson, words, opts)
+}
+
+func (f *FakeAPI) GridSection(ctx context.Context, boundingBox v3.BoundingBox, callOpts ...v3.CallOption) (*v3.GridSectionJsonResponse, error) {
+	return call[*v3.GridSectionJsonResponse](ctx, f.reg, MethodGridSection, boundingBox, nil)
+}
+
+func (f *FakeAPI) GridSectionGeoJson(ctx context.Context, boundingBox v3.BoundingBox, callOpts ...v3.CallOption) (*v3.GridSectionGeoJsonResponse, error) {
+	return call[*v3.GridSectionGeoJsonResponse](ctx, f.reg, MethodGridSectionGeoJson, boundingBox, nil)
+}
+
+func (f *FakeAPI) AutoSuggest(ctx context.Context, input string, opts *v3.AutoSuggestOpts, callOpts ...v3.CallOption) (*v3.AutoSuggestResponse, error) {
+	return call[*v3.AutoSuggestResponse](ctx, f.reg, MethodAutoSuggest, input, opts)
+}
+
+func (f *FakeAPI) AutoSuggestWithCoordinates(ctx context.Context, input string, opts *v3.AutoSuggestOpts, callOpts ...v3.CallOption) (*v3.AutoSuggestWithCoordinatesResponse, error) {
+	return call[*v3.AutoSuggestWithCoordinatesResponse](ctx, f.reg, MethodAutoSuggestWithCoordinates, input, opts)
+}
+
+func (f *FakeAPI) AutoSuggestSelection(ctx context.Context, rawInput string, selection v3.AutoSuggestSuggestion, opts *v3.AutoSuggestOpts, callOpts ...v3.CallOption) error {
+	_, err := call[struct{}](ctx, f.reg, MethodAutoSuggestSelection, Selection{RawInput: rawInput, Suggestion: selection}, opts)
+	return err
+}
+
+func (f *FakeAPI) AvailableLanguages(ctx context.Context, callOpts ...v3.CallOption) (*v3.AvailableLanguagesResponse, error) {
+	return call[*v3.AvailableLanguagesResponse](ctx, f.reg, MethodAvailableLanguages, nil, nil)
+}
+
+// call returns the result scripted for the call. opts is recorded as nil
+// when it is a nil pointer.
+func call[T any](ctx context.Context, reg *fake.Registry, method string, input any, opts any) (T, error) {
+	var zero T
+	switch o := opts.(type) {
+	case *v3.ConvertAPIOpts:
+		if o == nil {
+			opts = nil
+		}
+	case *v3.AutoSuggestOpts:
+		if o == nil {
+			opts = nil
+		}
+	}
+	value, _, err := reg.Call(ctx, method, input, opts)
+	if err != nil {
+		return zero, err
+	}
+	resp, _ := value.(T)
+	return resp, nil
+}
@@ -0,0 +1,124 @@
+package v3fake_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/apis/v3/v3fake"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+// recorder counts the failures reported by assertions.
+type recorder struct {
+	testing.TB
+	failures int
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures++
+}
+
+func TestScriptedResponses(t *testing.T) {
+	api := v3fake.New()
+	ctx := context.Background()
+	london := core.Coordinates{Lat: 51.520847, Lng: -0.195521}
+
+	api.OnConvertTo3wa(london).Return(&v3.ConvertAPIJsonResponse{Words: "filled.count.soap"})
+	api.OnConvertTo3wa().ReturnError(v3.ErrBadCoordinates)
+	api.OnAutoSuggest("filled.count.so").
+		ReturnError(v3.ErrInternalServerError).
+		Return(&v3.AutoSuggestResponse{Suggestions: []v3.AutoSuggestSuggestion{{Words: "filled.count.soap"}}})
+
+	resp, err := api.ConvertTo3wa(ctx, london, &v3.ConvertAPIOpts{Language: "en"})
+	if err != nil || resp.Words != "filled.count.soap" {
+		t.Fatalf("ERROR: Expected the response scripted for %v, got %+v, %v", london, resp, err)
+	}
+	if _, err := api.ConvertTo3wa(ctx, core.Coordinates{Lat: 1, Lng: 2}, nil); !errors.Is(err, v3.ErrBadCoordinates) {
+		t.Fatalf("ERROR: Expected the error scripted for any coordinates, got %v", err)
+	}
+
+	if _, err := api.AutoSuggest(ctx, "filled.count.so", nil); !errors.Is(err, v3.ErrInternalServerError) {
+		t.Fatalf("ERROR: Expected the first scripted result to be an error, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		suggestions, err := api.AutoSuggest(ctx, "filled.count.so", nil)
+		if err != nil || len(suggestions.Suggestions) != 1 {
+			t.Fatalf("ERROR: Expected the last scripted result to be repeated, got %+v, %v", suggestions, err)
+		}
+	}
+
+	if _, err := api.AvailableLanguages(ctx); !errors.Is(err, v3fake.ErrUnscripted) {
+		t.Fatalf("ERROR: Expected ErrUnscripted, got %v", err)
+	}
+	if _, err := api.AutoSuggest(ctx, "index.home.raft", nil); !errors.Is(err, v3fake.ErrUnscripted) {
+		t.Fatalf("ERROR: Expected ErrUnscripted for an input without scripted result, got %v", err)
+	}
+}
+
+func TestCallRecording(t *testing.T) {
+	api := v3fake.New()
+	ctx := context.Background()
+	api.OnConvertToCoordinates().Return(&v3.ConvertAPIJsonResponse{})
+	opts := &v3.ConvertAPIOpts{Language: "de"}
+
+	api.ConvertToCoordinates(ctx, "filled.count.soap", opts)
+	api.ConvertToCoordinates(ctx, "index.home.raft", nil)
+	api.Clone(v3.WithoutValidation()).ConvertToCoordinates(ctx, "daring.lion.race", nil)
+
+	api.AssertCalled(t, v3fake.MethodConvertToCoordinates)
+	api.AssertCalled(t, v3fake.MethodConvertToCoordinates, "index.home.raft")
+	api.AssertCallCount(t, v3fake.MethodConvertToCoordinates, 3)
+	api.AssertNotCalled(t, v3fake.MethodGridSection)
+
+	calls := api.Calls(v3fake.MethodConvertToCoordinates)
+	if calls[0].Input != "filled.count.soap" || calls[0].Opts != opts || calls[1].Opts != nil {
+		t.Fatalf("ERROR: Unexpected recorded calls %+v", calls)
+	}
+	if calls[2].Input != "daring.lion.race" {
+		t.Fatalf("ERROR: Expected calls made through a clone to be recorded, got %+v", calls[2])
+	}
+
+	rec := &recorder{TB: t}
+	api.AssertCalled(rec, v3fake.MethodConvertToCoordinates, "limit.broom.flip")
+	api.AssertCallCount(rec, v3fake.MethodConvertToCoordinates, 1)
+	if rec.failures != 2 {
+		t.Fatal("ERROR: Expected the failing assertions to fail the test")
+	}
+
+	api.SetHeader(core.HEADER_API_KEY, "test-key")
+	api.SetBaseURL("https://example.com/v3")
+	if api.Header(core.HEADER_API_KEY) != "test-key" || api.BaseURL() != "https://example.com/v3" {
+		t.Fatal("ERROR: Expected the setters to be recorded")
+	}
+
+	api.Reset()
+	if len(api.Calls()) != 0 {
+		t.Fatalf("ERROR: Expected no calls after Reset, got %v", api.Calls())
+	}
+}
+
+func TestLatency(t *testing.T) {
+	api := v3fake.New()
+	api.OnAvailableLanguages().Return(&v3.AvailableLanguagesResponse{})
+	api.OnGridSection().After(20 * time.Millisecond).Return(&v3.GridSectionJsonResponse{})
+	api.SetLatency(v3fake.MethodAvailableLanguages, time.Hour)
+
+	start := time.Now()
+	if _, err := api.GridSection(context.Background(), v3.BoundingBox{}); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Fatalf("ERROR: Expected the result to be delayed by 20ms, took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := api.AvailableLanguages(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ERROR: Expected the latency to honour the context, got %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+package v3
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+// ValidationError is returned by the Validate methods, and by the endpoints
+// unless validation is disabled with WithoutValidation, listing every
+// problem found with the request options.
+type ValidationError = core.ValidationError
+
+// FieldError describes a problem with a single field of a request option.
+type FieldError = core.FieldError
+
+const (
+	// maxPolygonPoints is the maximum number of points accepted by clip-to-polygon.
+	maxPolygonPoints = 25
+	// maxNResults is the maximum number of results returned by autosuggest.
+	maxNResults = 100
+	// defaultNResults is the number of results returned by autosuggest
+	// when NResults is not set.
+	defaultNResults = 3
+)
+
+var (
+	regexLanguage = regexp.MustCompile(`^[a-z]{2}$`)
+	regexLocale   = regexp.MustCompile(`^[a-z]{2}_[a-z]{2}$`)
+	regexCountry  = regexp.MustCompile(`^[a-zA-Z]{2}$`)
+)
+
+// validateLanguage records problems with the format of the language and
+// locale options, and with a locale which is not a variant of the language.
+func validateLanguage(ve *ValidationError, language, locale string) {
+	if language != "" && !regexLanguage.MatchString(language) {
+		ve.Add("Language", "must be an ISO 639-1 2 letter lowercase code, got %q", language)
+	}
+	if locale != "" && !regexLocale.MatchString(locale) {
+		ve.Add("Locale", "must be in the form <language>_<variant>, got %q", locale)
+	}
+	if language != "" && locale != "" && !strings.HasPrefix(locale, language+"_") {
+		ve.Add("Locale", "%q is not a locale of language %q", locale, language)
+	}
+}
+
+// Validate checks the language and locale are well formed.
+func (cto ConvertAPIOpts) Validate() error {
+	var ve ValidationError
+	validateLanguage(&ve, cto.Language, cto.Locale)
+	return ve.Err()
+}
+
+// ValidateWith checks the options as Validate does, and checks the language
+// and locale are available in the catalog, loading it if needed.
+func (cto ConvertAPIOpts) ValidateWith(ctx context.Context, catalog *LanguageCatalog) error {
+	var ve ValidationError
+	ve.Merge("", cto.Validate())
+	if err := catalog.check(ctx, &ve, cto.Language, cto.Locale); err != nil {
+		return err
+	}
+	return ve.Err()
+}
+
+// Validate checks both corners are valid coordinates and that the
+// south west corner is below and to the left of the north east corner.
+// Longitudes are allowed to wrap, a bounding box crossing the
+// anti-meridian is specified using an east longitude greater than 180.
+func (bb BoundingBox) Validate() error {
+	var ve ValidationError
+	ve.Merge("SouthWest", bb.SouthWest.Validate())
+	ve.Merge("NorthEast", bb.NorthEast.Validate())
+	if bb.SouthWest.Lat > bb.NorthEast.Lat {
+		ve.Add("SouthWest.Lat", "must be less than or equal to NorthEast.Lat")
+	}
+	if bb.SouthWest.Lng > bb.NorthEast.Lng {
+		ve.Add("SouthWest.Lng", "must be less than or equal to NorthEast.Lng")
+	}
+	return ve.Err()
+}
+
+// Validate checks the center is a valid coordinate and the radius is
+// a positive number.
+func (c Circle) Validate() error {
+	var ve ValidationError
+	ve.Merge("Center", c.Center.Validate())
+	if math.IsNaN(c.RadiusKm) || math.IsInf(c.RadiusKm, 0) || c.RadiusKm <= 0 {
+		ve.Add("RadiusKm", "must be a positive number, got %v", c.RadiusKm)
+	}
+	return ve.Err()
+}
+
+// validatePolygon records problems with a polygon which is not closed, does
+// not have between 4 and 25 points or contains invalid coordinates.
+func validatePolygon(ve *ValidationError, field string, p Polygon) {
+	if len(p) < 4 || len(p) > maxPolygonPoints {
+		ve.Add(field, "must contain between 4 and %d points, got %d", maxPolygonPoints, len(p))
+	}
+	for i, point := range p {
+		ve.Merge(fmt.Sprintf("%s[%d]", field, i), point.Validate())
+	}
+	if len(p) > 0 && p[0] != p[len(p)-1] {
+		ve.Add(field, "must be closed, the first point must be repeated as the last point")
+	}
+}
+
+// Validate checks every option against the limits documented by the API,
+// such as closed polygons of at most 25 points, at most 100 results,
+// 2 letter country codes and a language for voice input types.
+func (aso AutoSuggestOpts) Validate() error {
+	var ve ValidationError
+	if aso.Focus != nil {
+		ve.Merge("Focus", aso.Focus.Validate())
+	}
+	for i, country := range aso.ClipToCountry {
+		if !regexCountry.MatchString(country) {
+			ve.Add(fmt.Sprintf("ClipToCountry[%d]", i), "must be an ISO 3166-1 alpha-2 country code, got %q", country)
+		}
+	}
+	if aso.ClipToBoundingBox != nil {
+		ve.Merge("ClipToBoundingBox", aso.ClipToBoundingBox.Validate())
+	}
+	if aso.ClipToCircle != nil {
+		ve.Merge("ClipToCircle", aso.ClipToCircle.Validate())
+	}
+	if aso.ClipToPolygon != nil {
+		validatePolygon(&ve, "ClipToPolygon", aso.ClipToPolygon)
+	}
+	validateLanguage(&ve, aso.Language, aso.Locale)
+	if aso.InputType != "" && !slices.Contains(InputTypes, aso.InputType) {
+		ve.Add("InputType", "must be one of the InputTypes, got %q", aso.InputType)
+	}
+	if aso.InputType.IsVoice() && aso.Language == "" {
+		ve.Add("Language", "is required for the %s input type", aso.InputType)
+	}
+	if aso.NResults != nil && (*aso.NResults < 1 || *aso.NResults > maxNResults) {
+		ve.Add("NResults", "must be between 1 and %d, got %d", maxNResults, *aso.NResults)
+	}
+	if aso.NFocusResult != nil {
+		switch {
+		case *aso.NFocusResult < 1 || *aso.NFocusResult > maxNResults:
+			ve.Add("NFocusResult", "must be between 1 and %d, got %d", maxNResults, *aso.NFocusResult)
+		case aso.NResults != nil && *aso.NFocusResult > *aso.NResults:
+			ve.Add("NFocusResult", "must be less than or equal to NResults")
+		case aso.NResults == nil && *aso.NFocusResult > defaultNResults:
+			ve.Add("NFocusResult", "must be less than or equal to the default NResults of %d", defaultNResults)
+		}
+		if aso.Focus == nil {
+			ve.Add("NFocusResult", "requires Focus to be set")
+		}
+	}
+	return ve.Err()
+}
+
+// ValidateWith checks the options as Validate does, and checks the language
+// and locale are available in the catalog, loading it if needed.
+func (aso AutoSuggestOpts) ValidateWith(ctx context.Context, catalog *LanguageCatalog) error {
+	var ve ValidationError
+	ve.Merge("", aso.Validate())
+	if err := catalog.check(ctx, &ve, aso.Language, aso.Locale); err != nil {
+		return err
+	}
+	return ve.Err()
+}
+
+// Validate checks the suggestion has words and a rank, as required to
+// report it to AutoSuggestSelection.
+func (s AutoSuggestSuggestion) Validate() error {
+	var ve ValidationError
+	if s.Words == "" {
+		ve.Add("Words", "must not be empty")
+	}
+	if s.Rank < 1 {
+		ve.Add("Rank", "must be at least 1, got %d", s.Rank)
+	}
+	return ve.Err()
+}
@@ -0,0 +1,169 @@
+package v3_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+func fieldsOf(err error) []string {
+	var validationErr *v3.ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil
+	}
+	fields := make([]string, 0, len(validationErr.Errors))
+	for _, fe := range validationErr.Errors {
+		fields = append(fields, fe.Field)
+	}
+	return fields
+}
+
+func TestAutoSuggestOptsValidate(t *testing.T) {
+	valid := v3.AutoSuggestOpts{
+		Focus:         &core.Coordinates{Lat: 51.52, Lng: -0.2},
+		ClipToCountry: []string{"GB", "be"},
+		ClipToPolygon: v3.Polygon{
+			{Lat: 51.521, Lng: -0.343},
+			{Lat: 52.6, Lng: 2.3324},
+			{Lat: 54.234, Lng: 8.343},
+			{Lat: 51.521, Lng: -0.343},
+		},
+		Language:     "oo",
+		Locale:       "oo_cy",
+		NResults:     core.Int(10),
+		NFocusResult: core.Int(5),
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("ERROR: Expected options to be valid, got %v", err)
+	}
+
+	invalid := v3.AutoSuggestOpts{
+		Focus:             &core.Coordinates{Lat: 91, Lng: 0},
+		ClipToCountry:     []string{"GBR"},
+		ClipToBoundingBox: &v3.BoundingBox{SouthWest: core.Coordinates{Lat: 52}, NorthEast: core.Coordinates{Lat: 51}},
+		ClipToCircle:      &v3.Circle{RadiusKm: -1},
+		ClipToPolygon: v3.Polygon{
+			{Lat: 51.521, Lng: -0.343},
+			{Lat: 52.6, Lng: 2.3324},
+			{Lat: 54.234, Lng: 8.343},
+		},
+		NResults: core.Int(101),
+	}
+	expected := []string{
+		"Focus.Lat",
+		"ClipToCountry[0]",
+		"ClipToBoundingBox.SouthWest.Lat",
+		"ClipToCircle.RadiusKm",
+		"ClipToPolygon",
+		"ClipToPolygon",
+		"NResults",
+	}
+	fields := fieldsOf(invalid.Validate())
+	if len(fields) != len(expected) {
+		t.Fatalf("ERROR: Expected problems with %v, got %v", expected, fields)
+	}
+	for i := range expected {
+		if fields[i] != expected[i] {
+			t.Fatalf("ERROR: Expected problems with %v, got %v", expected, fields)
+		}
+	}
+}
+
+func TestAutoSuggestNFocusResultDefault(t *testing.T) {
+	focus := &core.Coordinates{Lat: 51.52, Lng: -0.2}
+	if fields := fieldsOf((v3.AutoSuggestOpts{Focus: focus, NFocusResult: core.Int(5)}).Validate()); len(fields) != 1 || fields[0] != "NFocusResult" {
+		t.Fatalf("ERROR: Expected NFocusResult to be limited by the default NResults, got %v", fields)
+	}
+	if err := (v3.AutoSuggestOpts{Focus: focus, NFocusResult: core.Int(3)}).Validate(); err != nil {
+		t.Fatalf("ERROR: Expected NFocusResult up to the default NResults to be valid, got %v", err)
+	}
+}
+
+func TestAutoSuggestInputType(t *testing.T) {
+	if fields := fieldsOf((v3.AutoSuggestOpts{InputType: v3.InputTypeGenericVoice}).Validate()); len(fields) != 1 || fields[0] != "Language" {
+		t.Fatalf("ERROR: Expected voice input types to require Language, got %v", fields)
+	}
+	if fields := fieldsOf((v3.AutoSuggestOpts{InputType: "morse"}).Validate()); len(fields) != 1 || fields[0] != "InputType" {
+		t.Fatalf("ERROR: Expected an unknown input type to be rejected, got %v", fields)
+	}
+	if err := (v3.AutoSuggestOpts{InputType: v3.InputTypeOCRAbbyy}).Validate(); err != nil {
+		t.Fatalf("ERROR: Expected the OCR input type not to require Language, got %v", err)
+	}
+
+	var query atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query.Store(r.URL.Query())
+		w.Write([]byte(`{"suggestions":[]}`))
+	}))
+	defer srv.Close()
+	api := v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL))
+	payload := `{"_isInGrammar":"yes","_hypotheses":[{"_items":[{"_orthography":"filled"},{"_orthography":"count"},{"_orthography":"soap"}]}]}`
+	opts := &v3.AutoSuggestOpts{InputType: v3.InputTypeVoconHybrid, Language: "en"}
+	if _, err := api.AutoSuggest(context.Background(), payload, opts); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if q := query.Load().(url.Values); q.Get("input") != payload || q.Get("input-type") != "vocon-hybrid" || q.Get("language") != "en" {
+		t.Fatalf("ERROR: Expected the payload to be sent unchanged with its input type, got %v", q)
+	}
+	err := api.AutoSuggestSelection(context.Background(), payload, v3.AutoSuggestSuggestion{Words: "filled.count.soap", Rank: 1}, opts)
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if q := query.Load().(url.Values); q.Get("source-api") != v3.SourceAPIVoice {
+		t.Fatalf("ERROR: Expected voice selections to be reported with the voice source API, got %v", q)
+	}
+}
+
+func TestConvertAPIOptsValidate(t *testing.T) {
+	if err := (v3.ConvertAPIOpts{Language: "mn", Locale: "mn_la"}).Validate(); err != nil {
+		t.Fatalf("ERROR: Expected options to be valid, got %v", err)
+	}
+	fields := fieldsOf((v3.ConvertAPIOpts{Language: "english", Locale: "zh_tr"}).Validate())
+	if len(fields) != 2 || fields[0] != "Language" || fields[1] != "Locale" {
+		t.Fatalf("ERROR: Expected problems with Language and Locale, got %v", fields)
+	}
+}
+
+func TestEndpointsValidateBeforeRequest(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	api := v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL))
+	_, err := api.ConvertTo3wa(context.Background(), core.Coordinates{Lat: -95, Lng: 0}, nil)
+	if fields := fieldsOf(err); len(fields) != 1 || fields[0] != "Coordinates.Lat" {
+		t.Fatalf("ERROR: Expected a problem with Coordinates.Lat, got %v", err)
+	}
+	if !v3.IsInputError(err) {
+		t.Fatal("ERROR: Expected validation errors to be input errors")
+	}
+	_, err = api.AutoSuggest(context.Background(), "filled.count.so", &v3.AutoSuggestOpts{NResults: core.Int(0)})
+	if fieldsOf(err) == nil {
+		t.Fatalf("ERROR: Expected a validation error, got %v", err)
+	}
+	err = api.AutoSuggestSelection(context.Background(), "", v3.AutoSuggestSuggestion{Words: "filled.count.soap"}, nil)
+	if fields := fieldsOf(err); len(fields) != 2 || fields[0] != "RawInput" || fields[1] != "Selection.Rank" {
+		t.Fatalf("ERROR: Expected problems with RawInput and Selection.Rank, got %v", err)
+	}
+	if calls.Load() != 0 {
+		t.Fatalf("ERROR: Expected no request to be sent, got %d", calls.Load())
+	}
+
+	api = v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL), v3.WithoutValidation())
+	if _, err := api.ConvertTo3wa(context.Background(), core.Coordinates{Lat: -95, Lng: 0}, nil); err != nil {
+		t.Fatalf("ERROR: Expected validation to be disabled, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("ERROR: Expected request to be sent, got %d", calls.Load())
+	}
+}
@@ -0,0 +1,65 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/what3words/w3w-go-wrapper/pkg/cache"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+var (
+	_ core.Cache = (*cache.LRU)(nil)
+	_ core.Cache = (*cache.File)(nil)
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := cache.NewLRU(2)
+	c.Set("a", []byte("1"), 0)
+	c.Set("b", []byte("2"), 0)
+	c.Get("a")
+	c.Set("c", []byte("3"), 0)
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("ERROR: Expected least recently used entry to be evicted")
+	}
+	if v, ok := c.Get("a"); !ok || string(v) != "1" {
+		t.Fatalf("ERROR: Expected entry a to be kept, got %q %v", v, ok)
+	}
+	if c.Len() != 2 {
+		t.Fatalf("ERROR: Expected 2 entries, got %d", c.Len())
+	}
+}
+
+func TestLRUExpiry(t *testing.T) {
+	c := cache.NewLRU(10)
+	c.Set("a", []byte("1"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("ERROR: Expected expired entry to be missed")
+	}
+}
+
+func TestFileSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	c, err := cache.NewFile(dir)
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	c.Set("convert-to-3wa?coordinates=1,2", []byte(`{"words":"a.b.c"}`), time.Hour)
+	c.Set("expired", []byte("x"), time.Nanosecond)
+
+	reopened, err := cache.NewFile(dir)
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if v, ok := reopened.Get("convert-to-3wa?coordinates=1,2"); !ok || string(v) != `{"words":"a.b.c"}` {
+		t.Fatalf("ERROR: Expected entry to survive restart, got %q %v", v, ok)
+	}
+	time.Sleep(time.Millisecond)
+	if _, ok := reopened.Get("expired"); ok {
+		t.Fatal("ERROR: Expected expired entry to be missed")
+	}
+	if _, ok := reopened.Get("missing"); ok {
+		t.Fatal("ERROR: Expected missing entry to be missed")
+	}
+}
@@ -0,0 +1,395 @@
+// Package cassette provides an HTTP client recording the requests made to
+// the What3Words API and their responses to a cassette file, and replaying
+// them later, so that tests run against real payloads without calling the
+// API.
+//
+// Example usage:
+//
+//	rec, err := cassette.New("testdata/convert.json", cassette.ModeReplay)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	api := v3.NewAPI(os.Getenv("X_API_KEY"), v3.WithClient(rec))
+//
+// Cassettes are recorded by running the same test with ModeRecord and a
+// real API key. The API key is redacted from recorded cassettes.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/what3words/w3w-go-wrapper/internal/client"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+// Mode selects whether a Recorder records, replays or passes requests
+// through.
+type Mode int
+
+const (
+	// ModeReplay answers requests with the recorded responses, without
+	// sending them. Requests without recorded response fail.
+	ModeReplay Mode = iota
+	// ModeRecord sends requests and records them with their responses,
+	// replacing the cassette.
+	ModeRecord
+	// ModePassthrough sends requests without recording them.
+	ModePassthrough
+)
+
+// String returns the name of the mode as accepted by ParseMode.
+func (m Mode) String() string {
+	switch m {
+	case ModeReplay:
+		return "replay"
+	case ModeRecord:
+		return "record"
+	case ModePassthrough:
+		return "passthrough"
+	}
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+// ParseMode returns the mode named s, being "replay", "record" or
+// "passthrough". An empty s is ModeReplay, so that tests replay by
+// default, for example with:
+//
+//	mode, err := cassette.ParseMode(os.Getenv("W3W_CASSETTE_MODE"))
+func ParseMode(s string) (Mode, error) {
+	switch strings.ToLower(s) {
+	case "", "replay":
+		return ModeReplay, nil
+	case "record":
+		return ModeRecord, nil
+	case "passthrough":
+		return ModePassthrough, nil
+	}
+	return 0, fmt.Errorf("cassette: unknown mode %q", s)
+}
+
+// Redacted replaces the redacted values in recorded cassettes.
+const Redacted = "REDACTED"
+
+// ErrNoInteraction is wrapped by the errors returned in ModeReplay for
+// requests without recorded response.
+var ErrNoInteraction = errors.New("cassette: no recorded interaction")
+
+// Request is a recorded request.
+type Request struct {
+	Method string `json:"method"`
+	// Endpoint is the last segment of the path of the request, such as
+	// convert-to-3wa, so that cassettes replay whatever the base URL.
+	Endpoint string `json:"endpoint"`
+	// Query is the normalized query of the request, see Recorder.
+	Query  string      `json:"query"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Cassette is the content of a cassette file.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder is a client.HttpClient recording, replaying or passing through
+// requests depending on its mode. It is safe for concurrent use.
+//
+// Requests are matched on their method, endpoint and normalized query. The
+// query is normalized by removing redacted parameters and sorting the
+// parameters and their values. Identical requests are answered with their
+// recorded responses in order, the last one being repeated.
+type Recorder struct {
+	path            string
+	mode            Mode
+	client          client.HttpClient
+	redactedHeaders []string
+	redactedParams  []string
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+var _ client.HttpClient = (*Recorder)(nil)
+
+// Option configures a Recorder.
+type Option func(*Recorder)
+
+// WithClient sets the client sending requests in ModeRecord and
+// ModePassthrough. Defaults to http.DefaultClient.
+func WithClient(client client.HttpClient) Option {
+	return func(r *Recorder) {
+		r.client = client
+	}
+}
+
+// WithRedactedHeaders redacts the values of more request headers, such as
+// headers holding tokens. The API key header is always redacted.
+func WithRedactedHeaders(headers ...string) Option {
+	return func(r *Recorder) {
+		for _, header := range headers {
+			r.redactedHeaders = append(r.redactedHeaders, http.CanonicalHeaderKey(header))
+		}
+	}
+}
+
+// WithRedactedParams redacts the values of more query parameters, and
+// ignores them when matching requests. The key parameter is always
+// redacted.
+func WithRedactedParams(params ...string) Option {
+	return func(r *Recorder) {
+		r.redactedParams = append(r.redactedParams, params...)
+	}
+}
+
+// New creates a Recorder for the cassette file at path. In ModeReplay the
+// cassette is read and must exist. In ModeRecord it is written after each
+// request, replacing its previous content.
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:            path,
+		mode:            mode,
+		client:          http.DefaultClient,
+		redactedHeaders: []string{http.CanonicalHeaderKey(core.HEADER_API_KEY)},
+		redactedParams:  []string{"key"},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	switch mode {
+	case ModeReplay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("cassette: reading %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	case ModeRecord, ModePassthrough:
+	default:
+		return nil, fmt.Errorf("cassette: unknown mode %v", mode)
+	}
+	return r, nil
+}
+
+// Mode returns the mode of the recorder.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Interactions returns the interactions recorded or loaded so far.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.cassette.Interactions...)
+}
+
+// Unused returns the loaded interactions which have not been replayed yet,
+// which usually means that the cassette is outdated.
+func (r *Recorder) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unused []Interaction
+	for i, used := range r.used {
+		if !used {
+			unused = append(unused, r.cassette.Interactions[i])
+		}
+	}
+	return unused
+}
+
+// Do sends, records or replays the request depending on the mode.
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	switch r.mode {
+	case ModeReplay:
+		return r.replay(req)
+	case ModeRecord:
+		return r.record(req)
+	}
+	return r.client.Do(req)
+}
+
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	recorded := r.request(req, nil)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	found := -1
+	for i, in := range r.cassette.Interactions {
+		if in.Request.Method != recorded.Method || in.Request.Endpoint != recorded.Endpoint || in.Request.Query != recorded.Query {
+			continue
+		}
+		found = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if found < 0 {
+		return nil, fmt.Errorf("%w in %s for %s %s?%s", ErrNoInteraction, r.path, recorded.Method, recorded.Endpoint, recorded.Query)
+	}
+	r.used[found] = true
+	return response(req, r.cassette.Interactions[found].Response), nil
+}
+
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	res, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	secrets := r.secrets(req)
+	in := Interaction{
+		Request: r.request(req, secrets),
+		Response: Response{
+			StatusCode: res.StatusCode,
+			Header:     redactHeader(res.Header, nil, secrets),
+			Body:       redact(string(body), secrets),
+		},
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, in)
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// save writes the cassette, replacing the file atomically.
+func (r *Recorder) save() error {
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("cassette: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), ".cassette-*")
+	if err != nil {
+		return fmt.Errorf("cassette: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("cassette: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("cassette: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		return fmt.Errorf("cassette: %w", err)
+	}
+	return nil
+}
+
+// secrets returns the values of the redacted headers and parameters of req.
+func (r *Recorder) secrets(req *http.Request) []string {
+	var secrets []string
+	for _, header := range r.redactedHeaders {
+		secrets = append(secrets, req.Header.Values(header)...)
+	}
+	query := req.URL.Query()
+	for _, param := range r.redactedParams {
+		secrets = append(secrets, query[param]...)
+	}
+	return secrets
+}
+
+// request returns req as recorded, redacting the secrets.
+func (r *Recorder) request(req *http.Request, secrets []string) Request {
+	query := req.URL.Query()
+	for _, param := range r.redactedParams {
+		delete(query, param)
+	}
+	for param, values := range query {
+		sort.Strings(values)
+		query[param] = values
+	}
+	u := *req.URL
+	u.User = nil
+	return Request{
+		Method:   req.Method,
+		Endpoint: path.Base(req.URL.Path),
+		Query:    query.Encode(),
+		URL:      redact(u.String(), secrets),
+		Header:   redactHeader(req.Header, r.redactedHeaders, secrets),
+	}
+}
+
+// redactHeader returns a copy of header with the values of the redacted
+// headers and the secrets replaced.
+func redactHeader(header http.Header, redacted []string, secrets []string) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	out := make(http.Header, len(header))
+	for name, values := range header {
+		for _, value := range values {
+			for _, r := range redacted {
+				if http.CanonicalHeaderKey(name) == r {
+					value = Redacted
+				}
+			}
+			out[name] = append(out[name], redact(value, secrets))
+		}
+	}
+	return out
+}
+
+func redact(s string, secrets []string) string {
+	for _, secret := range secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, Redacted)
+		}
+	}
+	return s
+}
+
+// response returns the recorded response as a response to req.
+func response(req *http.Request, recorded Response) *http.Response {
+	header := recorded.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}
+}
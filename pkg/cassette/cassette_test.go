@@ -0,0 +1,135 @@
+package cassette_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/cassette"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+	"github.com/what3words/w3w-go-wrapper/pkg/w3wtest"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "convert.json")
+	point := core.Coordinates{Lat: 51.520847, Lng: -0.195521}
+	policy := core.DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	ctx := context.Background()
+
+	srv := w3wtest.NewServer(w3wtest.WithAPIKey("secret-key"))
+	srv.FailNext(v3.EndpointConvertTo3wa, 1, w3wtest.Error(http.StatusServiceUnavailable, v3.ErrorCodeInternalServerError, "Server Error"))
+	rec, err := cassette.New(path, cassette.ModeRecord)
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	api := v3.NewAPI("secret-key", v3.WithCustomBaseURL(srv.URL), v3.WithClient(rec), v3.WithRetryPolicy(policy))
+	recorded, err := api.ConvertTo3wa(ctx, point, &v3.ConvertAPIOpts{Language: "de"})
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if _, err := api.ConvertToCoordinates(ctx, "filled.count.soap", nil); !errors.Is(err, v3.ErrBadWords) {
+		t.Fatalf("ERROR: Expected ErrBadWords, got %v", err)
+	}
+	srv.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ERROR: Expected the cassette to be written, got %v", err)
+	}
+	if strings.Contains(string(data), "secret-key") || !strings.Contains(string(data), cassette.Redacted) {
+		t.Fatalf("ERROR: Expected the API key to be redacted, got %s", data)
+	}
+	if n := len(rec.Interactions()); n != 3 {
+		t.Fatalf("ERROR: Expected 3 recorded interactions, got %d", n)
+	}
+
+	rec, err = cassette.New(path, cassette.ModeReplay)
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	api = v3.NewAPI("other-key", v3.WithCustomBaseURL("http://replay.invalid"), v3.WithClient(rec), v3.WithRetryPolicy(policy))
+	replayed, err := api.ConvertTo3wa(ctx, point, &v3.ConvertAPIOpts{Language: "de"})
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if *replayed != *recorded {
+		t.Fatalf("ERROR: Expected the recorded response %+v, got %+v", recorded, replayed)
+	}
+	if _, err := api.ConvertToCoordinates(ctx, "filled.count.soap", nil); !errors.Is(err, v3.ErrBadWords) {
+		t.Fatalf("ERROR: Expected the recorded ErrBadWords, got %v", err)
+	}
+	if unused := rec.Unused(); len(unused) != 0 {
+		t.Fatalf("ERROR: Expected every interaction to be replayed, got %+v", unused)
+	}
+
+	_, err = api.ConvertTo3wa(ctx, point, &v3.ConvertAPIOpts{Language: "fr"})
+	if !errors.Is(err, cassette.ErrNoInteraction) || !strings.Contains(err.Error(), "language=fr") {
+		t.Fatalf("ERROR: Expected ErrNoInteraction for an unrecorded request, got %v", err)
+	}
+}
+
+func TestReplayNormalizesQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	srv := w3wtest.NewServer()
+	defer srv.Close()
+	rec, err := cassette.New(path, cassette.ModeRecord, cassette.WithRedactedParams("session"))
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/v3/autosuggest?input=index.home.ra&clip-to-country=GB&clip-to-country=FR&key=test-key&session=1", nil)
+	res, err := rec.Do(req)
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	res.Body.Close()
+
+	rec, err = cassette.New(path, cassette.ModeReplay, cassette.WithRedactedParams("session"))
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	req, _ = http.NewRequest(http.MethodGet, "https://api.what3words.com/v3/autosuggest?clip-to-country=FR&clip-to-country=GB&session=2&input=index.home.ra", nil)
+	res, err = rec.Do(req)
+	if err != nil {
+		t.Fatalf("ERROR: Expected the request to match regardless of parameter order, got %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("ERROR: Expected the recorded status 200, got %d", res.StatusCode)
+	}
+	if url := rec.Interactions()[0].Request.URL; strings.Contains(url, "test-key") || strings.Contains(url, "session=1") {
+		t.Fatalf("ERROR: Expected the key and session to be redacted from %s", url)
+	}
+}
+
+func TestModes(t *testing.T) {
+	if _, err := cassette.New(filepath.Join(t.TempDir(), "missing.json"), cassette.ModeReplay); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("ERROR: Expected replaying a missing cassette to fail, got %v", err)
+	}
+	for _, mode := range []cassette.Mode{cassette.ModeReplay, cassette.ModeRecord, cassette.ModePassthrough} {
+		if parsed, err := cassette.ParseMode(mode.String()); err != nil || parsed != mode {
+			t.Fatalf("ERROR: Expected %v to be parsed, got %v, %v", mode, parsed, err)
+		}
+	}
+	if _, err := cassette.ParseMode("rewind"); err == nil {
+		t.Fatal("ERROR: Expected an unknown mode to be rejected")
+	}
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	srv := w3wtest.NewServer()
+	defer srv.Close()
+	rec, _ := cassette.New(path, cassette.ModePassthrough)
+	api := v3.NewAPI("test-key", v3.WithCustomBaseURL(srv.URL), v3.WithClient(rec))
+	if _, err := api.AvailableLanguages(context.Background()); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("ERROR: Expected nothing to be recorded in passthrough mode, got %v", err)
+	}
+}
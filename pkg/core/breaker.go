@@ -0,0 +1,218 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned, without sending a request, while a
+// CircuitBreaker is open or has as many half-open probes in flight
+// as it allows.
+var ErrCircuitOpen = errors.New("w3w: circuit breaker is open")
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through, counting failures.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects every request with ErrCircuitOpen until
+	// the cool-down has elapsed.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe requests through
+	// to decide whether to close or open the circuit again.
+	CircuitHalfOpen
+)
+
+func (cs CircuitState) String() string {
+	switch cs {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerSettings configures a CircuitBreaker. Zero values are
+// replaced by their defaults.
+type CircuitBreakerSettings struct {
+	// FailureThreshold is the number of consecutive failed attempts which
+	// opens the circuit. Defaults to 5.
+	FailureThreshold int
+	// CoolDown is how long the circuit stays open before letting probe
+	// requests through. Defaults to 30 seconds.
+	CoolDown time.Duration
+	// HalfOpenRequests is the number of probe requests allowed concurrently
+	// while half-open, and the number of them which must succeed to close
+	// the circuit. Defaults to 1.
+	HalfOpenRequests int
+	// OnStateChange, when set, is called whenever the circuit changes state.
+	// It is called synchronously by the request triggering the change, after
+	// the breaker has been updated.
+	OnStateChange func(from, to CircuitState)
+}
+
+// CircuitBreaker stops sending requests to a failing backend, failing fast
+// with ErrCircuitOpen instead, until the backend has had time to recover.
+//
+// Every attempt failing with a transport error or a 5xx status code counts
+// as a failure, unless the context of the call was cancelled or reached its
+// deadline first. Once FailureThreshold consecutive attempts have failed the
+// circuit opens. After CoolDown it becomes half-open and lets up to
+// HalfOpenRequests probes through: it closes once they all succeed, and
+// opens again on the first failure.
+//
+// It is safe for concurrent use, so a single CircuitBreaker can be shared
+// by several APIs calling the same backend.
+type CircuitBreaker struct {
+	mu       sync.Mutex
+	settings CircuitBreakerSettings
+	state    CircuitState
+	// failures counts consecutive failures while closed, successes
+	// and inFlight count probes while half-open.
+	failures  int
+	successes int
+	inFlight  int
+	openedAt  time.Time
+	// generation changes with every state change, so that outcomes of
+	// attempts started in a previous state are ignored.
+	generation uint64
+}
+
+// NewCircuitBreaker creates a closed CircuitBreaker.
+func NewCircuitBreaker(settings CircuitBreakerSettings) *CircuitBreaker {
+	if settings.FailureThreshold < 1 {
+		settings.FailureThreshold = 5
+	}
+	if settings.CoolDown <= 0 {
+		settings.CoolDown = 30 * time.Second
+	}
+	if settings.HalfOpenRequests < 1 {
+		settings.HalfOpenRequests = 1
+	}
+	return &CircuitBreaker{settings: settings}
+}
+
+// State returns the current state of the circuit. An open circuit whose
+// cool-down has elapsed is reported open until the next request.
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
+
+// outcome of an attempt, as recorded by the breaker.
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	// outcomeIgnored is recorded for attempts abandoned by the caller,
+	// which say nothing about the health of the backend.
+	outcomeIgnored
+)
+
+// attemptOutcome classifies an attempt sent with ctx. Errors caused by ctx
+// ending, whether cancelled or past a deadline set by the caller, are ignored.
+func attemptOutcome(ctx context.Context, resp *http.Response, err error) outcome {
+	switch {
+	case errors.Is(err, context.Canceled), err != nil && ctx.Err() != nil:
+		return outcomeIgnored
+	case err != nil:
+		return outcomeFailure
+	case resp.StatusCode >= 500:
+		return outcomeFailure
+	}
+	return outcomeSuccess
+}
+
+// allow reports whether an attempt may be sent, returning the generation
+// its outcome must be recorded with. A nil breaker allows every attempt.
+func (cb *CircuitBreaker) allow() (uint64, error) {
+	if cb == nil {
+		return 0, nil
+	}
+	cb.mu.Lock()
+	from := cb.state
+	if cb.state == CircuitOpen {
+		if time.Since(cb.openedAt) < cb.settings.CoolDown {
+			cb.mu.Unlock()
+			return 0, ErrCircuitOpen
+		}
+		cb.setState(CircuitHalfOpen)
+	}
+	if cb.state == CircuitHalfOpen {
+		if cb.inFlight >= cb.settings.HalfOpenRequests {
+			cb.mu.Unlock()
+			cb.notify(from, CircuitHalfOpen)
+			return 0, ErrCircuitOpen
+		}
+		cb.inFlight++
+	}
+	generation, to := cb.generation, cb.state
+	cb.mu.Unlock()
+	cb.notify(from, to)
+	return generation, nil
+}
+
+// record updates the breaker with the outcome of an attempt allowed
+// in the given generation.
+func (cb *CircuitBreaker) record(generation uint64, o outcome) {
+	if cb == nil {
+		return
+	}
+	cb.mu.Lock()
+	if generation != cb.generation {
+		cb.mu.Unlock()
+		return
+	}
+	from := cb.state
+	switch cb.state {
+	case CircuitClosed:
+		switch o {
+		case outcomeSuccess:
+			cb.failures = 0
+		case outcomeFailure:
+			cb.failures++
+			if cb.failures >= cb.settings.FailureThreshold {
+				cb.setState(CircuitOpen)
+			}
+		}
+	case CircuitHalfOpen:
+		cb.inFlight--
+		switch o {
+		case outcomeSuccess:
+			cb.successes++
+			if cb.successes >= cb.settings.HalfOpenRequests {
+				cb.setState(CircuitClosed)
+			}
+		case outcomeFailure:
+			cb.setState(CircuitOpen)
+		}
+	}
+	to := cb.state
+	cb.mu.Unlock()
+	cb.notify(from, to)
+}
+
+// setState moves the breaker to a new state, must be called with mu held.
+func (cb *CircuitBreaker) setState(state CircuitState) {
+	cb.state = state
+	cb.generation++
+	cb.failures, cb.successes, cb.inFlight = 0, 0, 0
+	if state == CircuitOpen {
+		cb.openedAt = time.Now()
+	}
+}
+
+func (cb *CircuitBreaker) notify(from, to CircuitState) {
+	if from != to && cb.settings.OnStateChange != nil {
+		cb.settings.OnStateChange(from, to)
+	}
+}
@@ -0,0 +1,136 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+func TestCircuitBreakerStates(t *testing.T) {
+	var healthy atomic.Bool
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	var mu sync.Mutex
+	var transitions []string
+	breaker := core.NewCircuitBreaker(core.CircuitBreakerSettings{
+		FailureThreshold: 3,
+		CoolDown:         50 * time.Millisecond,
+		OnStateChange: func(from, to core.CircuitState) {
+			mu.Lock()
+			defer mu.Unlock()
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	})
+	get := func() error {
+		var fk FakeResponse
+		return core.Get(context.Background(), core.Request{
+			Client:  http.DefaultClient,
+			BaseURL: srv.URL,
+			Breaker: breaker,
+		}, &fk)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := get(); errors.Is(err, core.ErrCircuitOpen) {
+			t.Fatalf("ERROR: Expected circuit to be closed on attempt %d", i+1)
+		}
+	}
+	if breaker.State() != core.CircuitOpen {
+		t.Fatalf("ERROR: Expected circuit to be open, got %s", breaker.State())
+	}
+	if err := get(); !errors.Is(err, core.ErrCircuitOpen) {
+		t.Fatalf("ERROR: Expected ErrCircuitOpen, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("ERROR: Expected no request while open, got %d requests", calls.Load())
+	}
+
+	// A failed probe opens the circuit again.
+	time.Sleep(60 * time.Millisecond)
+	get()
+	if breaker.State() != core.CircuitOpen {
+		t.Fatalf("ERROR: Expected failed probe to open the circuit, got %s", breaker.State())
+	}
+
+	// A successful probe closes it.
+	healthy.Store(true)
+	time.Sleep(60 * time.Millisecond)
+	if err := get(); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if breaker.State() != core.CircuitClosed {
+		t.Fatalf("ERROR: Expected circuit to be closed, got %s", breaker.State())
+	}
+
+	expected := []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(transitions) != len(expected) {
+		t.Fatalf("ERROR: Expected transitions %v, got %v", expected, transitions)
+	}
+	for i := range expected {
+		if transitions[i] != expected[i] {
+			t.Fatalf("ERROR: Expected transitions %v, got %v", expected, transitions)
+		}
+	}
+}
+
+func TestCircuitBreakerStopsRetries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	policy := testRetryPolicy()
+	policy.MaxAttempts = 10
+	var fk FakeResponse
+	err := core.Get(context.Background(), core.Request{
+		Client:  http.DefaultClient,
+		BaseURL: srv.URL,
+		Retry:   policy,
+		Breaker: core.NewCircuitBreaker(core.CircuitBreakerSettings{FailureThreshold: 2}),
+	}, &fk)
+	if !errors.Is(err, core.ErrCircuitOpen) {
+		t.Fatalf("ERROR: Expected ErrCircuitOpen, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("ERROR: Expected retries to stop once the circuit opens, got %d requests", calls.Load())
+	}
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	breaker := core.NewCircuitBreaker(core.CircuitBreakerSettings{FailureThreshold: 1})
+	var fk FakeResponse
+	core.Get(context.Background(), core.Request{
+		Client:  http.DefaultClient,
+		BaseURL: srv.URL,
+		Breaker: breaker,
+	}, &fk)
+	if breaker.State() != core.CircuitClosed {
+		t.Fatalf("ERROR: Expected 4xx responses not to open the circuit, got %s", breaker.State())
+	}
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Cache stores raw response bodies of successful requests, keyed by the
+// requested URL and a fingerprint of the API key. Implementations must be
+// safe for concurrent use. See the w3w-go-wrapper/pkg/cache package for
+// in-memory and file backed implementations.
+type Cache interface {
+	// Get returns the value stored for key, if present and not expired.
+	Get(key string) ([]byte, bool)
+	// Set stores the value for key, expiring after ttl. A ttl of zero
+	// or less means the value never expires.
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// cacheKey identifies a response by the URL it was requested from and the
+// API key it was requested with, so that responses are never served to
+// calls made to another backend or with another key. The key itself is
+// not stored, only a fingerprint of it.
+func cacheKey(u *url.URL, headers map[string]string) string {
+	return u.String() + "#" + fingerprint(headers)
+}
+
+// fingerprint returns a fingerprint of the API key of the headers.
+func fingerprint(headers map[string]string) string {
+	var apiKey string
+	for key, value := range headers {
+		if strings.EqualFold(key, HEADER_API_KEY) {
+			apiKey = value
+		}
+	}
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Target identifies the endpoint a request with the given base URL and
+// headers is sent to, and the API key it is sent with, for caches keeping
+// responses of different backends or keys apart. With a pool, it is the
+// endpoint the request would be sent to first, with its own API key if it
+// has one. The key itself is not part of the target, only a fingerprint.
+func Target(baseURL string, pool *EndpointPool, headers map[string]string) string {
+	if pool != nil {
+		ep := pool.peek()
+		baseURL, headers = ep.URL, ep.headers(headers)
+	}
+	return target(baseURL, headers)
+}
+
+func target(baseURL string, headers map[string]string) string {
+	return baseURL + "#" + fingerprint(headers)
+}
@@ -0,0 +1,18 @@
+package core
+
+import "context"
+
+type endpointContextKey struct{}
+
+// ContextWithEndpoint returns a copy of ctx carrying the logical name of
+// the API endpoint a request is made to, such as `convert-to-3wa`.
+func ContextWithEndpoint(ctx context.Context, endpoint string) context.Context {
+	return context.WithValue(ctx, endpointContextKey{}, endpoint)
+}
+
+// EndpointFromContext returns the logical name of the API endpoint carried
+// by ctx, or an empty string if there is none.
+func EndpointFromContext(ctx context.Context) string {
+	endpoint, _ := ctx.Value(endpointContextKey{}).(string)
+	return endpoint
+}
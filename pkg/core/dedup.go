@@ -0,0 +1,96 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Deduplicator shares a single in-flight request between identical requests
+// made concurrently, which all receive its response. Requests are identical
+// when they have the same URL and headers. It is safe for concurrent use.
+//
+// The shared request is only cancelled once every caller waiting for it has
+// given up, so a caller whose context expires returns early with its context
+// error without affecting the others.
+type Deduplicator struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// flight is a request in progress, shared by its waiters.
+type flight struct {
+	done    chan struct{}
+	res     result
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// NewDeduplicator creates an empty Deduplicator.
+func NewDeduplicator() *Deduplicator {
+	return &Deduplicator{flights: make(map[string]*flight)}
+}
+
+// dedupKey identifies identical requests by URL and headers.
+func dedupKey(rawURL string, headers map[string]string) string {
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	sb.WriteString(rawURL)
+	for _, key := range keys {
+		sb.WriteString("\n")
+		sb.WriteString(http.CanonicalHeaderKey(key))
+		sb.WriteString(": ")
+		sb.WriteString(headers[key])
+	}
+	return sb.String()
+}
+
+// do calls fn, unless an identical call is already in flight in which case
+// its result is awaited instead. The context passed to fn keeps the values
+// of ctx but is only cancelled when every waiter has left. shared reports
+// whether the result was obtained by another caller.
+func (d *Deduplicator) do(ctx context.Context, key string, fn func(context.Context) (result, error)) (res result, shared bool, err error) {
+	d.mu.Lock()
+	f, shared := d.flights[key]
+	if !shared {
+		flightCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		d.flights[key] = f
+		go func() {
+			defer cancel()
+			f.res, f.err = fn(flightCtx)
+			d.mu.Lock()
+			if d.flights[key] == f {
+				delete(d.flights, key)
+			}
+			d.mu.Unlock()
+			close(f.done)
+		}()
+	}
+	f.waiters++
+	d.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.res, shared, f.err
+	case <-ctx.Done():
+		d.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// Nobody is left waiting, later callers start a new request.
+			if d.flights[key] == f {
+				delete(d.flights, key)
+			}
+			f.cancel()
+		}
+		d.mu.Unlock()
+		return result{}, shared, ctx.Err()
+	}
+}
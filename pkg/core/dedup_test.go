@@ -0,0 +1,156 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+func TestGetDeduplicatesConcurrentRequests(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	dedup := core.NewDeduplicator()
+	var wg sync.WaitGroup
+	metas := make([]core.ResponseMeta, 10)
+	errs := make([]error, 10)
+	for i := range metas {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var fk FakeResponse
+			errs[i] = core.Get(context.Background(), core.Request{
+				Client:      http.DefaultClient,
+				BaseURL:     srv.URL,
+				QueryParams: map[string]string{"words": "filled.count.soap"},
+				Meta:        &metas[i],
+				Dedup:       dedup,
+			}, &fk)
+			if errs[i] == nil && fk["ok"] != true {
+				errs[i] = errors.New("missing response")
+			}
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Fatalf("ERROR: Expected 1 request, got %d", calls.Load())
+	}
+	var shared int
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("ERROR: Got error %v", err)
+		}
+		if metas[i].StatusCode != http.StatusOK {
+			t.Fatalf("ERROR: Expected status 200, got %d", metas[i].StatusCode)
+		}
+		if metas[i].Shared {
+			shared++
+		}
+	}
+	if shared != 9 {
+		t.Fatalf("ERROR: Expected 9 shared responses, got %d", shared)
+	}
+}
+
+func TestGetDeduplicationDistinguishesHeaders(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	dedup := core.NewDeduplicator()
+	var wg sync.WaitGroup
+	for _, key := range []string{"a", "b"} {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			var fk FakeResponse
+			core.Get(context.Background(), core.Request{
+				Client:  http.DefaultClient,
+				BaseURL: srv.URL,
+				Headers: map[string]string{core.HEADER_API_KEY: key},
+				Dedup:   dedup,
+			}, &fk)
+		}(key)
+	}
+	wg.Wait()
+	if calls.Load() != 2 {
+		t.Fatalf("ERROR: Expected 2 requests, got %d", calls.Load())
+	}
+}
+
+func TestGetDeduplicationCancellation(t *testing.T) {
+	var calls atomic.Int32
+	cancelled := make(chan struct{})
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			// The first request is abandoned by every caller.
+			<-r.Context().Done()
+			close(cancelled)
+			return
+		}
+		<-release
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	dedup := core.NewDeduplicator()
+	get := func(ctx context.Context) error {
+		var fk FakeResponse
+		return core.Get(ctx, core.Request{
+			Client:  http.DefaultClient,
+			BaseURL: srv.URL,
+			Dedup:   dedup,
+		}, &fk)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := get(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ERROR: Expected context.DeadlineExceeded, got %v", err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("ERROR: Expected the abandoned request to be cancelled")
+	}
+
+	// A caller leaving early does not cancel the request for the others.
+	early, cancelEarly := context.WithCancel(context.Background())
+	errEarly := make(chan error, 1)
+	go func() { errEarly <- get(early) }()
+	errLate := make(chan error, 1)
+	go func() { errLate <- get(context.Background()) }()
+	time.Sleep(20 * time.Millisecond)
+	cancelEarly()
+	if err := <-errEarly; !errors.Is(err, context.Canceled) {
+		t.Fatalf("ERROR: Expected context.Canceled, got %v", err)
+	}
+	close(release)
+	if err := <-errLate; err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("ERROR: Expected 2 requests, got %d", calls.Load())
+	}
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// httpErrorBodyLimit is the maximum number of bytes of the
+// response body kept in an HTTPError.
+const httpErrorBodyLimit = 1024
+
+// HTTPError is returned by Get when the API responds with a non 2xx
+// status code, or with a body that can not be decoded as JSON.
+// When the response body contains an error object returned by the API,
+// it is wrapped by the HTTPError and can be retrieved using `errors.As`.
+//
+// Example inferance:
+//
+//	var httpErr *core.HTTPError
+//	if errors.As(err, &httpErr) {
+//		fmt.Println(httpErr.StatusCode)
+//	}
+type HTTPError struct {
+	// StatusCode of the response.
+	StatusCode int
+	// Endpoint is the URL path the request was sent to.
+	Endpoint string
+	// Header of the response.
+	Header http.Header
+	// Body is the raw response body, truncated to its first 1024 bytes.
+	Body []byte
+	// Err is the error decoded from the response body, or the error
+	// encountered while decoding it. Nil when neither is available.
+	Err error
+}
+
+func newHTTPError(resp *http.Response, endpoint string, body []byte, err error) *HTTPError {
+	if len(body) > httpErrorBodyLimit {
+		body = body[:httpErrorBodyLimit]
+	}
+	return &HTTPError{
+		StatusCode: resp.StatusCode,
+		Endpoint:   endpoint,
+		Header:     resp.Header,
+		Body:       body,
+		Err:        err,
+	}
+}
+
+func (he *HTTPError) Error() string {
+	if he.Err != nil {
+		return fmt.Sprintf("api: %s responded with status %d: %v", he.Endpoint, he.StatusCode, he.Err)
+	}
+	return fmt.Sprintf("api: %s responded with status %d and body %q", he.Endpoint, he.StatusCode, he.Body)
+}
+
+func (he *HTTPError) Unwrap() error {
+	return he.Err
+}
@@ -0,0 +1,63 @@
+package core_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+func TestGetReturnsHTTPErrorForNonJSONBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html>" + strings.Repeat("x", 4096) + "</html>"))
+	}))
+	defer srv.Close()
+
+	var fk FakeResponse
+	err := core.MakeGetRequest(context.Background(), http.DefaultClient, srv.URL, nil, nil, &fk, "v3", "convert-to-3wa")
+	var httpErr *core.HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("ERROR: Expected *core.HTTPError, got %T (%v)", err, err)
+	}
+	if httpErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("ERROR: Expected status %d, got %d", http.StatusBadGateway, httpErr.StatusCode)
+	}
+	if httpErr.Endpoint != "/v3/convert-to-3wa" {
+		t.Fatalf("ERROR: Expected endpoint /v3/convert-to-3wa, got %s", httpErr.Endpoint)
+	}
+	if httpErr.Header.Get("Content-Type") != "text/html" {
+		t.Fatalf("ERROR: Expected response headers to be kept, got %v", httpErr.Header)
+	}
+	if len(httpErr.Body) != 1024 || !strings.HasPrefix(string(httpErr.Body), "<html>") {
+		t.Fatalf("ERROR: Expected body to be truncated to 1024 bytes, got %d", len(httpErr.Body))
+	}
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("ERROR: Expected decoding error to be wrapped, got %v", httpErr.Err)
+	}
+}
+
+func TestGetReturnsHTTPErrorWithoutErrorObject(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var fk FakeResponse
+	err := core.MakeGetRequest(context.Background(), http.DefaultClient, srv.URL, nil, nil, &fk)
+	var httpErr *core.HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("ERROR: Expected *core.HTTPError, got %T (%v)", err, err)
+	}
+	if httpErr.Err != nil {
+		t.Fatalf("ERROR: Expected no wrapped error, got %v", httpErr.Err)
+	}
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"net/http"
+	"time"
+)
+
+// requestIDHeaders are the response headers checked, in order,
+// for an identifier of the request assigned by the server.
+var requestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id", "X-Amzn-Trace-Id"}
+
+// ResponseMeta holds metadata about the response received for a request,
+// alongside the decoded response body.
+type ResponseMeta struct {
+	// Endpoint is the URL path the request was sent to.
+	Endpoint string
+	// StatusCode of the last response received, zero if none was received.
+	StatusCode int
+	// Header of the last response received, including any rate limit headers.
+	Header http.Header
+	// RequestID assigned to the request by the server, if any.
+	RequestID string
+	// Duration is the total time taken by the request, including
+	// retries and time spent waiting between attempts.
+	Duration time.Duration
+	// Attempts is the number of attempts made to send the request.
+	Attempts int
+	// Cached is true when the response was served from a cache,
+	// in which case no request was sent and Header is nil.
+	Cached bool
+	// Shared is true when the response was received by an identical
+	// request already in flight, shared instead of sending a new one.
+	Shared bool
+	// Target identifies the endpoint the response was received from and
+	// the API key it was requested with, see Target.
+	Target string
+}
+
+func (rm *ResponseMeta) fill(endpoint string, res result, duration time.Duration) {
+	*rm = ResponseMeta{
+		Endpoint: endpoint,
+		Duration: duration,
+		Attempts: res.attempts,
+		Target:   res.target,
+	}
+	if res.resp == nil {
+		return
+	}
+	rm.StatusCode = res.resp.StatusCode
+	rm.Header = res.resp.Header
+	for _, header := range requestIDHeaders {
+		if id := res.resp.Header.Get(header); id != "" {
+			rm.RequestID = id
+			break
+		}
+	}
+}
+
+// FillCached fills rm as Get does for a response served from a cache, for
+// caches answering calls outside of Get. The Endpoint is the path of the URL
+// the request would have been sent to.
+func (rm *ResponseMeta) FillCached(baseURL string, paths ...string) {
+	var endpoint string
+	if u, err := requestURL(baseURL, Request{Paths: paths}); err == nil {
+		endpoint = u.Path
+	}
+	rm.fillCached(endpoint)
+}
+
+func (rm *ResponseMeta) fillCached(endpoint string) {
+	*rm = ResponseMeta{
+		Endpoint:   endpoint,
+		StatusCode: http.StatusOK,
+		Cached:     true,
+	}
+}
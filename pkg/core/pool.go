@@ -0,0 +1,306 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/what3words/w3w-go-wrapper/internal/client"
+)
+
+// Endpoint is a server the API can be reached at.
+type Endpoint struct {
+	// URL endpoint names are joined to, including any path prefix the API
+	// is served under, for example https://api.what3words.com/v3.
+	URL string
+	// APIKey, when set, replaces the API key of requests sent to the endpoint.
+	APIKey string
+}
+
+// RoutingStrategy decides which healthy endpoint of an EndpointPool
+// requests are sent to.
+type RoutingStrategy int
+
+const (
+	// RoutePriority sends requests to the first healthy endpoint,
+	// in the order they were given.
+	RoutePriority RoutingStrategy = iota
+	// RouteRoundRobin spreads requests evenly across healthy endpoints.
+	RouteRoundRobin
+)
+
+// EndpointPoolSettings configures an EndpointPool. Zero values are
+// replaced by their defaults.
+type EndpointPoolSettings struct {
+	// Strategy used to route requests. Defaults to RoutePriority.
+	Strategy RoutingStrategy
+	// FailureThreshold is the number of consecutive failed attempts which
+	// ejects an endpoint from the pool. Defaults to 3.
+	FailureThreshold int
+	// ProbeInterval is the time between probes of an ejected endpoint.
+	// Defaults to 10 seconds.
+	ProbeInterval time.Duration
+	// ProbePath is joined to the URL of an ejected endpoint to probe it,
+	// and must name a route answering a GET without parameters. The
+	// endpoint is re-admitted once a probe receives a 2xx response.
+	// Defaults to available-languages.
+	ProbePath string
+	// OnHealthChange, when set, is called whenever an endpoint is
+	// ejected or re-admitted.
+	OnHealthChange func(endpoint Endpoint, healthy bool)
+}
+
+// EndpointPool routes requests across several endpoints, for example
+// self-hosted enterprise servers with the public API as a fallback.
+//
+// Every attempt is sent to an endpoint chosen according to the routing
+// strategy. An attempt failing with a transport error or a 5xx status code
+// is immediately sent again to the next healthy endpoint not yet tried by
+// the request, without waiting or counting towards the retry policy. Endpoints
+// failing FailureThreshold consecutive attempts are ejected, and probed in
+// the background until they recover. When every endpoint is ejected, requests
+// are sent to all of them in turn anyway.
+//
+// It is safe for concurrent use, and can be shared by several APIs. Close
+// stops the background probes once the pool is no longer used.
+type EndpointPool struct {
+	mu        sync.Mutex
+	settings  EndpointPoolSettings
+	endpoints []*poolEndpoint
+	next      int
+	// ctx is cancelled by Close, stopping the probes tracked by probes.
+	ctx    context.Context
+	cancel context.CancelFunc
+	probes sync.WaitGroup
+}
+
+type poolEndpoint struct {
+	Endpoint
+	failures int
+	ejected  bool
+}
+
+// NewEndpointPool creates an EndpointPool routing requests across the given
+// endpoints, all initially healthy.
+func NewEndpointPool(endpoints []Endpoint, settings EndpointPoolSettings) (*EndpointPool, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("w3w: endpoint pool requires at least one endpoint")
+	}
+	if settings.FailureThreshold < 1 {
+		settings.FailureThreshold = 3
+	}
+	if settings.ProbeInterval <= 0 {
+		settings.ProbeInterval = 10 * time.Second
+	}
+	if settings.ProbePath == "" {
+		settings.ProbePath = "available-languages"
+	}
+	pool := &EndpointPool{settings: settings}
+	pool.ctx, pool.cancel = context.WithCancel(context.Background())
+	for _, endpoint := range endpoints {
+		if _, err := url.Parse(endpoint.URL); err != nil {
+			return nil, fmt.Errorf("w3w: invalid endpoint URL %q: %w", endpoint.URL, err)
+		}
+		pool.endpoints = append(pool.endpoints, &poolEndpoint{Endpoint: endpoint})
+	}
+	return pool, nil
+}
+
+// Close stops probing ejected endpoints, waiting for probes in flight to
+// return. Ejected endpoints are no longer re-admitted, requests are still
+// routed by the pool.
+func (p *EndpointPool) Close() {
+	p.mu.Lock()
+	p.cancel()
+	p.mu.Unlock()
+	p.probes.Wait()
+}
+
+// Healthy returns the endpoints which are not ejected, in the order
+// they were given.
+func (p *EndpointPool) Healthy() []Endpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var healthy []Endpoint
+	for _, ep := range p.endpoints {
+		if !ep.ejected {
+			healthy = append(healthy, ep.Endpoint)
+		}
+	}
+	return healthy
+}
+
+// pick returns the endpoint the next attempt of a request is sent to,
+// preferring healthy endpoints not yet tried by the request.
+func (p *EndpointPool) pick(tried []*poolEndpoint) *poolEndpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	candidates := p.candidates(tried)
+	if len(candidates) == 0 {
+		for _, ep := range p.endpoints {
+			if !ep.ejected {
+				candidates = append(candidates, ep)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		// Every endpoint is ejected, go through all of them in turn.
+		candidates = p.endpoints
+		if len(tried) > 0 {
+			return candidates[len(tried)%len(candidates)]
+		}
+	}
+	if p.settings.Strategy == RouteRoundRobin {
+		p.next++
+		return candidates[p.next%len(candidates)]
+	}
+	return candidates[0]
+}
+
+// peek returns the endpoint the next request would be sent to first,
+// without advancing the round robin.
+func (p *EndpointPool) peek() *poolEndpoint {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	candidates := p.candidates(nil)
+	if len(candidates) == 0 {
+		return p.endpoints[0]
+	}
+	if p.settings.Strategy == RouteRoundRobin {
+		return candidates[(p.next+1)%len(candidates)]
+	}
+	return candidates[0]
+}
+
+// canFailOver reports whether a healthy endpoint has not yet been
+// tried by the request.
+func (p *EndpointPool) canFailOver(tried []*poolEndpoint) bool {
+	if p == nil {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.candidates(tried)) > 0
+}
+
+// candidates returns the healthy endpoints not yet tried, must be
+// called with mu held.
+func (p *EndpointPool) candidates(tried []*poolEndpoint) []*poolEndpoint {
+	var candidates []*poolEndpoint
+	for _, ep := range p.endpoints {
+		if ep.ejected || containsEndpoint(tried, ep) {
+			continue
+		}
+		candidates = append(candidates, ep)
+	}
+	return candidates
+}
+
+func containsEndpoint(endpoints []*poolEndpoint, ep *poolEndpoint) bool {
+	for _, e := range endpoints {
+		if e == ep {
+			return true
+		}
+	}
+	return false
+}
+
+// record updates the health of the endpoint with the outcome of an attempt,
+// ejecting it and starting to probe it with the client and API key of the
+// request once it failed too many times, unless the pool is closed.
+func (p *EndpointPool) record(ep *poolEndpoint, o outcome, hc client.HttpClient, headers map[string]string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	switch o {
+	case outcomeSuccess:
+		ep.failures = 0
+	case outcomeFailure:
+		ep.failures++
+	}
+	eject := !ep.ejected && ep.failures >= p.settings.FailureThreshold
+	probe := eject && p.ctx.Err() == nil
+	if eject {
+		ep.ejected = true
+	}
+	if probe {
+		p.probes.Add(1)
+	}
+	p.mu.Unlock()
+	if eject {
+		p.notify(ep.Endpoint, false)
+	}
+	if probe {
+		go p.probe(ep, hc, probeHeaders(headers))
+	}
+}
+
+// probe periodically sends a request to an ejected endpoint, re-admitting
+// it once it responds with a 2xx.
+func (p *EndpointPool) probe(ep *poolEndpoint, hc client.HttpClient, headers map[string]string) {
+	defer p.probes.Done()
+	ticker := time.NewTicker(p.settings.ProbeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		target, err := url.JoinPath(ep.URL, p.settings.ProbePath)
+		if err != nil {
+			return
+		}
+		ctx, cancel := context.WithTimeout(p.ctx, p.settings.ProbeInterval)
+		resp, _, err := do(ctx, hc, target, ep.headers(headers))
+		cancel()
+		if err != nil || resp.StatusCode < 200 || resp.StatusCode > 299 {
+			continue
+		}
+		p.mu.Lock()
+		ep.ejected, ep.failures = false, 0
+		p.mu.Unlock()
+		p.notify(ep.Endpoint, true)
+		return
+	}
+}
+
+func (p *EndpointPool) notify(endpoint Endpoint, healthy bool) {
+	if p.settings.OnHealthChange != nil {
+		p.settings.OnHealthChange(endpoint, healthy)
+	}
+}
+
+// probeHeaders returns the headers of the request which are also sent with
+// probes, being the API key and wrapper headers, leaving out any header
+// specific to the failed request.
+func probeHeaders(headers map[string]string) map[string]string {
+	kept := make(map[string]string, 2)
+	for key, value := range headers {
+		if strings.EqualFold(key, HEADER_API_KEY) || strings.EqualFold(key, HEADER_WRAPPER) {
+			kept[key] = value
+		}
+	}
+	return kept
+}
+
+// headers returns the headers of requests sent to the endpoint,
+// replacing the API key by its own if it has one.
+func (ep *poolEndpoint) headers(headers map[string]string) map[string]string {
+	if ep.APIKey == "" {
+		return headers
+	}
+	replaced := make(map[string]string, len(headers))
+	for key, value := range headers {
+		if !strings.EqualFold(key, HEADER_API_KEY) {
+			replaced[key] = value
+		}
+	}
+	replaced[HEADER_API_KEY] = ep.APIKey
+	return replaced
+}
@@ -0,0 +1,263 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+// poolServer counts requests and fails them with a 503 while unhealthy.
+type poolServer struct {
+	*httptest.Server
+	calls     atomic.Int32
+	unhealthy atomic.Bool
+	apiKey    atomic.Value
+}
+
+func newPoolServer(t *testing.T) *poolServer {
+	ps := &poolServer{}
+	ps.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ps.calls.Add(1)
+		ps.apiKey.Store(r.Header.Get(core.HEADER_API_KEY))
+		if ps.unhealthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(ps.Close)
+	return ps
+}
+
+func poolGet(pool *core.EndpointPool, meta *core.ResponseMeta) error {
+	var fk FakeResponse
+	return core.Get(context.Background(), core.Request{
+		Client:  http.DefaultClient,
+		Paths:   []string{"convert-to-3wa"},
+		Headers: map[string]string{core.HEADER_API_KEY: "default-key"},
+		Meta:    meta,
+		Pool:    pool,
+	}, &fk)
+}
+
+func TestEndpointPoolFailsOver(t *testing.T) {
+	primary, secondary := newPoolServer(t), newPoolServer(t)
+	primary.unhealthy.Store(true)
+	pool, err := core.NewEndpointPool([]core.Endpoint{
+		{URL: primary.URL + "/v3"},
+		{URL: secondary.URL + "/enterprise/v3", APIKey: "secondary-key"},
+	}, core.EndpointPoolSettings{})
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+
+	var meta core.ResponseMeta
+	if err := poolGet(pool, &meta); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if primary.calls.Load() != 1 || secondary.calls.Load() != 1 {
+		t.Fatalf("ERROR: Expected 1 request to each endpoint, got %d and %d", primary.calls.Load(), secondary.calls.Load())
+	}
+	if meta.Attempts != 2 || meta.Endpoint != "/enterprise/v3/convert-to-3wa" {
+		t.Fatalf("ERROR: Expected 2 attempts ending on the secondary, got %d to %s", meta.Attempts, meta.Endpoint)
+	}
+	if primary.apiKey.Load() != "default-key" || secondary.apiKey.Load() != "secondary-key" {
+		t.Fatalf("ERROR: Expected endpoint API keys, got %v and %v", primary.apiKey.Load(), secondary.apiKey.Load())
+	}
+}
+
+func TestEndpointPoolEjectsAndReadmits(t *testing.T) {
+	primary, secondary := newPoolServer(t), newPoolServer(t)
+	primary.unhealthy.Store(true)
+	health := make(chan bool, 2)
+	pool, err := core.NewEndpointPool([]core.Endpoint{
+		{URL: primary.URL},
+		{URL: secondary.URL},
+	}, core.EndpointPoolSettings{
+		FailureThreshold: 2,
+		ProbeInterval:    10 * time.Millisecond,
+		OnHealthChange: func(endpoint core.Endpoint, healthy bool) {
+			health <- healthy
+		},
+	})
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := poolGet(pool, nil); err != nil {
+			t.Fatalf("ERROR: Got error %v", err)
+		}
+	}
+	if healthy := <-health; healthy {
+		t.Fatal("ERROR: Expected primary to be ejected")
+	}
+	if endpoints := pool.Healthy(); len(endpoints) != 1 || endpoints[0].URL != secondary.URL {
+		t.Fatalf("ERROR: Expected only the secondary to be healthy, got %v", endpoints)
+	}
+
+	calls := primary.calls.Load()
+	poolGet(pool, nil)
+	if primary.calls.Load() > calls+1 {
+		t.Fatal("ERROR: Expected requests to skip the ejected primary")
+	}
+
+	primary.unhealthy.Store(false)
+	select {
+	case healthy := <-health:
+		if !healthy {
+			t.Fatal("ERROR: Expected primary to be re-admitted")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ERROR: Expected primary to be re-admitted by the probe")
+	}
+	if len(pool.Healthy()) != 2 {
+		t.Fatalf("ERROR: Expected both endpoints to be healthy, got %v", pool.Healthy())
+	}
+}
+
+func TestEndpointPoolCloseStopsProbes(t *testing.T) {
+	primary, secondary := newPoolServer(t), newPoolServer(t)
+	primary.unhealthy.Store(true)
+	pool, err := core.NewEndpointPool([]core.Endpoint{{URL: primary.URL}, {URL: secondary.URL}}, core.EndpointPoolSettings{
+		FailureThreshold: 1,
+		ProbeInterval:    5 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	poolGet(pool, nil)
+	calls := primary.calls.Load()
+	deadline := time.Now().Add(time.Second)
+	for primary.calls.Load() == calls && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if primary.calls.Load() == calls {
+		t.Fatal("ERROR: Expected the ejected primary to be probed")
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		pool.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("ERROR: Expected Close to stop the probe")
+	}
+	calls = primary.calls.Load()
+	time.Sleep(50 * time.Millisecond)
+	if primary.calls.Load() != calls {
+		t.Fatalf("ERROR: Expected no probes after Close, got %d more requests", primary.calls.Load()-calls)
+	}
+}
+
+func TestEndpointPoolRoundRobin(t *testing.T) {
+	first, second := newPoolServer(t), newPoolServer(t)
+	pool, err := core.NewEndpointPool([]core.Endpoint{{URL: first.URL}, {URL: second.URL}}, core.EndpointPoolSettings{
+		Strategy: core.RouteRoundRobin,
+	})
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		if err := poolGet(pool, nil); err != nil {
+			t.Fatalf("ERROR: Got error %v", err)
+		}
+	}
+	if first.calls.Load() != 5 || second.calls.Load() != 5 {
+		t.Fatalf("ERROR: Expected requests to be spread evenly, got %d and %d", first.calls.Load(), second.calls.Load())
+	}
+}
+
+func TestNewEndpointPoolRequiresEndpoints(t *testing.T) {
+	if _, err := core.NewEndpointPool(nil, core.EndpointPoolSettings{}); err == nil {
+		t.Fatal("ERROR: Expected an error for an empty pool")
+	}
+}
+
+func TestEndpointPoolProbeRequires2xx(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	probed := make(chan string, 100)
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case probed <- r.URL.Path:
+		default:
+		}
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer primary.Close()
+	secondary := newPoolServer(t)
+	pool, err := core.NewEndpointPool([]core.Endpoint{{URL: primary.URL + "/v3"}, {URL: secondary.URL}}, core.EndpointPoolSettings{
+		FailureThreshold: 1,
+		ProbeInterval:    5 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	defer pool.Close()
+	poolGet(pool, nil)
+	<-probed
+
+	status.Store(http.StatusNotFound)
+	for i := 0; i < 3; i++ {
+		if path := <-probed; path != "/v3/available-languages" {
+			t.Fatalf("ERROR: Expected probes of /v3/available-languages by default, got %s", path)
+		}
+	}
+	if len(pool.Healthy()) != 1 {
+		t.Fatalf("ERROR: Expected a probe answered with a 404 not to re-admit the primary, got %v", pool.Healthy())
+	}
+
+	status.Store(http.StatusOK)
+	deadline := time.Now().Add(time.Second)
+	for len(pool.Healthy()) != 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if len(pool.Healthy()) != 2 {
+		t.Fatal("ERROR: Expected a probe answered with a 200 to re-admit the primary")
+	}
+}
+
+func TestEndpointPoolIgnoresCallerDeadline(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer slow.Close()
+	pool, err := core.NewEndpointPool([]core.Endpoint{{URL: slow.URL}}, core.EndpointPoolSettings{FailureThreshold: 1})
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	defer pool.Close()
+	breaker := core.NewCircuitBreaker(core.CircuitBreakerSettings{FailureThreshold: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	var fk FakeResponse
+	err = core.Get(ctx, core.Request{
+		Client:  http.DefaultClient,
+		Paths:   []string{"convert-to-3wa"},
+		Pool:    pool,
+		Breaker: breaker,
+	}, &fk)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ERROR: Expected context.DeadlineExceeded, got %v", err)
+	}
+	if len(pool.Healthy()) != 1 {
+		t.Fatal("ERROR: Expected the deadline of the caller not to eject the endpoint")
+	}
+	if breaker.State() != core.CircuitClosed {
+		t.Fatalf("ERROR: Expected the deadline of the caller not to open the circuit, got %s", breaker.State())
+	}
+}
@@ -0,0 +1,84 @@
+package core
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter blocks a request until it is allowed to be sent.
+type Limiter interface {
+	// Wait blocks until a request may proceed or the context is done,
+	// in which case the context error is returned.
+	Wait(ctx context.Context) error
+}
+
+// RateLimiter is a token bucket Limiter. The bucket holds up to `burst`
+// tokens and is refilled at `rate` tokens per second, every request
+// consumes a single token. It is safe for concurrent use, so a single
+// RateLimiter can be shared by any number of goroutines.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a RateLimiter allowing `rate` requests per second
+// on average, with bursts of up to `burst` requests. The bucket starts full.
+// A burst below 1 is treated as 1.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	b := math.Max(float64(burst), 1)
+	return &RateLimiter{
+		rate:   rate,
+		burst:  b,
+		tokens: b,
+		last:   time.Now(),
+	}
+}
+
+// Wait reserves a token, blocking until it becomes available. If the context
+// is done before that, the reservation is cancelled and the context error
+// is returned.
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	delay := rl.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+	if err := sleep(ctx, delay); err != nil {
+		rl.cancel()
+		return err
+	}
+	return nil
+}
+
+// reserve takes a token from the bucket, possibly leaving it in debt,
+// and returns how long the caller has to wait for it.
+func (rl *RateLimiter) reserve(now time.Time) time.Duration {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if elapsed := now.Sub(rl.last); elapsed > 0 {
+		rl.tokens = math.Min(rl.burst, rl.tokens+elapsed.Seconds()*rl.rate)
+		rl.last = now
+	}
+	rl.tokens--
+	if rl.tokens >= 0 {
+		return 0
+	}
+	if rl.rate <= 0 {
+		return math.MaxInt64
+	}
+	return time.Duration(-rl.tokens / rl.rate * float64(time.Second))
+}
+
+// cancel returns a reserved token to the bucket.
+func (rl *RateLimiter) cancel() {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.tokens = math.Min(rl.burst, rl.tokens+1)
+}
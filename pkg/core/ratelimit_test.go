@@ -0,0 +1,52 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+func TestRateLimiterAllowsBurst(t *testing.T) {
+	rl := core.NewRateLimiter(1, 3)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	for i := 0; i < 3; i++ {
+		if err := rl.Wait(ctx); err != nil {
+			t.Fatalf("ERROR: Expected request %d within burst to pass, got %v", i, err)
+		}
+	}
+	if err := rl.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ERROR: Expected request over burst to block until deadline, got %v", err)
+	}
+}
+
+func TestRateLimiterRefills(t *testing.T) {
+	rl := core.NewRateLimiter(100, 1)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := rl.Wait(context.Background()); err != nil {
+			t.Fatalf("ERROR: Got error %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Fatalf("ERROR: Expected 5 requests at 100/s to take at least 40ms, took %v", elapsed)
+	}
+}
+
+func TestRateLimiterCancelReturnsToken(t *testing.T) {
+	rl := core.NewRateLimiter(10, 1)
+	rl.Wait(context.Background())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := rl.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ERROR: Expected context.Canceled, got %v", err)
+	}
+	start := time.Now()
+	rl.Wait(context.Background())
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Fatalf("ERROR: Cancelled reservation was not returned, waited %v", elapsed)
+	}
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how requests made through Get are retried
+// when they fail with a retryable condition. A retryable condition is
+// either a transport error (as long as the caller's context is still
+// alive) or a response whose status code is listed in
+// RetryableStatusCodes.
+//
+// Backoff between attempts grows exponentially from InitialBackoff by
+// Multiplier, capped at MaxBackoff, and is randomised by Jitter. When
+// the server sends a `Retry-After` header, its value, capped at
+// MaxRetryAfter, is used instead of the computed backoff.
+//
+// Example usage:
+//
+//	policy := core.DefaultRetryPolicy()
+//	policy.MaxAttempts = 5
+//	api := v3.NewAPI("your-api-key", v3.WithRetryPolicy(policy))
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a single
+	// request, including the first one. Values below 1 are treated as 1.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the computed exponential backoff. Zero means no cap.
+	MaxBackoff time.Duration
+	// Multiplier is applied to the backoff after every attempt.
+	// Values below 1 are treated as 1.
+	Multiplier float64
+	// MaxRetryAfter caps the delay requested by a `Retry-After` header, so
+	// that a server can not stall callers without deadline indefinitely.
+	// Zero caps it at MaxBackoff, and means no cap if MaxBackoff is zero too.
+	MaxRetryAfter time.Duration
+	// Jitter is the fraction, between 0 and 1, of each backoff that is
+	// randomised. A Jitter of 0.2 waits between 80% and 100% of the backoff.
+	Jitter float64
+	// RetryableStatusCodes lists the HTTP status codes that are retried.
+	RetryableStatusCodes []int
+}
+
+// DefaultRetryPolicy returns a RetryPolicy making up to 3 attempts,
+// starting with a 200ms backoff doubling up to 5s, with 20% jitter,
+// honouring Retry-After delays of up to 30s and retrying on 429, 502,
+// 503 and 504 status codes.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		MaxRetryAfter:  30 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+func (rp *RetryPolicy) attempts() int {
+	if rp == nil || rp.MaxAttempts < 1 {
+		return 1
+	}
+	return rp.MaxAttempts
+}
+
+func (rp *RetryPolicy) isRetryableStatus(statusCode int) bool {
+	return rp != nil && slices.Contains(rp.RetryableStatusCodes, statusCode)
+}
+
+// backoff returns the delay to wait after the given attempt (starting at 1).
+func (rp *RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := math.Max(rp.Multiplier, 1)
+	delay := float64(rp.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if rp.MaxBackoff > 0 && delay > float64(rp.MaxBackoff) {
+		delay = float64(rp.MaxBackoff)
+	}
+	jitter := math.Min(math.Max(rp.Jitter, 0), 1)
+	delay -= delay * jitter * rand.Float64()
+	return time.Duration(delay)
+}
+
+// capRetryAfter caps the delay requested by a `Retry-After` header.
+func (rp *RetryPolicy) capRetryAfter(delay time.Duration) time.Duration {
+	limit := rp.MaxRetryAfter
+	if limit <= 0 {
+		limit = rp.MaxBackoff
+	}
+	if limit > 0 && delay > limit {
+		return limit
+	}
+	return delay
+}
+
+// retryAfter parses the `Retry-After` header, which is either a number
+// of seconds or an HTTP date.
+func retryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// sleep waits for the given duration or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
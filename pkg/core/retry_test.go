@@ -0,0 +1,125 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+func testRetryPolicy() *core.RetryPolicy {
+	policy := core.DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+	return &policy
+}
+
+func TestGetRetriesRetryableStatus(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	var fk FakeResponse
+	err := core.Get(context.Background(), core.Request{
+		Client:  http.DefaultClient,
+		BaseURL: srv.URL,
+		Retry:   testRetryPolicy(),
+	}, &fk)
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("ERROR: Expected 3 attempts, got %d", calls.Load())
+	}
+	if fk["ok"] != true {
+		t.Fatalf("ERROR: Expected response of the last attempt, got %v", fk)
+	}
+}
+
+func TestGetHonoursRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	policy := testRetryPolicy()
+	policy.MaxRetryAfter = 100 * time.Millisecond
+	var fk FakeResponse
+	start := time.Now()
+	err := core.Get(context.Background(), core.Request{
+		Client:  http.DefaultClient,
+		BaseURL: srv.URL,
+		Retry:   policy,
+	}, &fk)
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	elapsed := time.Since(start)
+	if elapsed < policy.MaxRetryAfter {
+		t.Fatalf("ERROR: Expected Retry-After to be honoured over the backoff, retried after %v", elapsed)
+	}
+	if elapsed > time.Second {
+		t.Fatalf("ERROR: Expected Retry-After to be capped at MaxRetryAfter, retried after %v", elapsed)
+	}
+}
+
+func TestGetDoesNotRetryNonRetryableStatus(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var fk FakeResponse
+	core.Get(context.Background(), core.Request{
+		Client:  http.DefaultClient,
+		BaseURL: srv.URL,
+		Retry:   testRetryPolicy(),
+	}, &fk)
+	if calls.Load() != 1 {
+		t.Fatalf("ERROR: Expected exactly 1 attempt, got %d", calls.Load())
+	}
+}
+
+func TestGetStopsRetryingOnContextCancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	policy := testRetryPolicy()
+	policy.MaxAttempts = 100
+	policy.InitialBackoff = time.Second
+	policy.MaxBackoff = time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var fk FakeResponse
+	err := core.Get(ctx, core.Request{
+		Client:  http.DefaultClient,
+		BaseURL: srv.URL,
+		Retry:   policy,
+	}, &fk)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ERROR: Expected context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -1,15 +1,52 @@
 package core
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 
 	"github.com/what3words/w3w-go-wrapper/internal/client"
 )
 
+// Request describes a single GET request to be made by Get.
+type Request struct {
+	// Client used to send the request.
+	Client client.HttpClient
+	// BaseURL the paths are joined to.
+	BaseURL string
+	// Paths joined to the BaseURL to form the request URL.
+	Paths []string
+	// QueryParams set on the request URL.
+	QueryParams map[string]string
+	// Headers set on the request.
+	Headers map[string]string
+	// Retry configures retries of failed attempts. When nil
+	// exactly one attempt is made.
+	Retry *RetryPolicy
+	// Limiters are waited on, in order, before every attempt.
+	Limiters []Limiter
+	// Meta, when set, is filled with metadata about the response.
+	Meta *ResponseMeta
+	// Cache, when set, is checked before sending the request and
+	// stores the body of successful responses for CacheTTL.
+	Cache    Cache
+	CacheTTL time.Duration
+	// Dedup, when set, shares the response of an identical request
+	// already in flight instead of sending a new one.
+	Dedup *Deduplicator
+	// Breaker, when set, is checked before every attempt and records
+	// its outcome.
+	Breaker *CircuitBreaker
+	// Pool, when set, routes every attempt to one of its endpoints
+	// instead of BaseURL.
+	Pool *EndpointPool
+}
+
 // MakeGetRequest makes a GET request to the specified URL.
 // Responses are unmarshalled into the response parameter, it
 // is expected that the response parameter is a pointer to a struct
@@ -23,42 +60,220 @@ func MakeGetRequest(
 	response ResponseReader,
 	paths ...string,
 ) error {
+	return Get(ctx, Request{
+		Client:      client,
+		BaseURL:     baseURL,
+		Paths:       paths,
+		QueryParams: queryParams,
+		Headers:     headers,
+	}, response)
+}
 
-	preparedURL, err := url.Parse(baseURL)
+// Get makes the GET request described by req, retrying it as configured
+// by req.Retry. When req.Cache holds a response for the same URL and API key,
+// it is used and no request is sent. With req.Pool, responses are cached with
+// the URL and API key of the endpoint they were received from, and looked up
+// with those of the endpoint the request would be sent to first. When req.Dedup is set and an
+// identical request is in flight, its response is used instead of sending a
+// new request. The final response is unmarshalled into the response
+// parameter in the same way as MakeGetRequest.
+//
+// A non 2xx response, or a response body which is not valid JSON,
+// results in an *HTTPError wrapping the error returned by the API. A 2xx
+// response with an empty body, such as the response of endpoints only
+// reporting an event, leaves response unchanged and is not cached.
+func Get(ctx context.Context, req Request, response ResponseReader) error {
+	baseURL, headers := req.BaseURL, req.Headers
+	if req.Pool != nil {
+		ep := req.Pool.peek()
+		baseURL, headers = ep.URL, ep.headers(req.Headers)
+	}
+	preparedURL, err := requestURL(baseURL, req)
+	if err != nil {
+		return err
+	}
+
+	if req.Cache != nil {
+		key := cacheKey(preparedURL, headers)
+		if body, ok := req.Cache.Get(key); ok && json.Unmarshal(body, response) == nil {
+			if req.Meta != nil {
+				req.Meta.fillCached(preparedURL.Path)
+				req.Meta.Target = target(baseURL, headers)
+			}
+			return nil
+		}
+	}
+
+	start := time.Now()
+	var res result
+	var shared bool
+	if req.Dedup != nil {
+		res, shared, err = req.Dedup.do(ctx, dedupKey(preparedURL.String(), req.Headers), func(ctx context.Context) (result, error) {
+			return doWithRetry(ctx, req, preparedURL)
+		})
+	} else {
+		res, err = doWithRetry(ctx, req, preparedURL)
+	}
+	path := preparedURL.Path
+	if res.path != "" {
+		path = res.path
+	}
+	if req.Meta != nil {
+		req.Meta.fill(path, res, time.Since(start))
+		req.Meta.Shared = shared
+	}
 	if err != nil {
 		return err
 	}
-	preparedURL = preparedURL.JoinPath(paths...)
+	if res.resp.StatusCode >= 200 && res.resp.StatusCode <= 299 && len(bytes.TrimSpace(res.body)) == 0 {
+		return nil
+	}
+	err = json.Unmarshal(res.body, response)
+	if res.resp.StatusCode < 200 || res.resp.StatusCode > 299 {
+		if err == nil {
+			err = response.GetError()
+		}
+		return newHTTPError(res.resp, path, res.body, err)
+	}
+	if err != nil {
+		return newHTTPError(res.resp, path, res.body, err)
+	}
+	if req.Cache != nil {
+		req.Cache.Set(cacheKey(res.url, res.headers), res.body, req.CacheTTL)
+	}
+	return nil
+}
+
+// requestURL joins the paths and query parameters of req to baseURL.
+func requestURL(baseURL string, req Request) (*url.URL, error) {
+	preparedURL, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	preparedURL = preparedURL.JoinPath(req.Paths...)
+	if !strings.HasPrefix(preparedURL.Path, "/") {
+		preparedURL.Path = "/" + preparedURL.Path
+	}
 	query := preparedURL.Query()
-	for qk, qv := range queryParams {
+	for qk, qv := range req.QueryParams {
 		query.Set(qk, qv)
 	}
 	preparedURL.RawQuery = query.Encode()
+	return preparedURL, nil
+}
+
+// result is the outcome of sending a request, possibly over several attempts.
+type result struct {
+	// resp is the last response received, its body is already closed.
+	resp *http.Response
+	// body of the last response received.
+	body []byte
+	// path of the URL the last response was received from.
+	path string
+	// url and headers the last response was received with.
+	url     *url.URL
+	headers map[string]string
+	// target identifies the endpoint and API key of the last response,
+	// see Target.
+	target string
+	// attempts made to send the request.
+	attempts int
+}
+
+// doWithRetry sends the request until it succeeds, fails with a non
+// retryable condition or runs out of attempts. When req.Pool is set,
+// every attempt is sent to an endpoint picked from the pool, and failed
+// attempts are sent again to the next healthy endpoint without counting
+// as a retry. The body of the last response is fully read and closed
+// before returning.
+func doWithRetry(ctx context.Context, req Request, preparedURL *url.URL) (result, error) {
+	var res result
+	var retries int
+	var tried []*poolEndpoint
+	maxAttempts := req.Retry.attempts()
+	for retries < maxAttempts {
+		generation, err := req.Breaker.allow()
+		if err != nil {
+			return res, err
+		}
+		for _, limiter := range req.Limiters {
+			if err := limiter.Wait(ctx); err != nil {
+				req.Breaker.record(generation, outcomeIgnored)
+				return res, err
+			}
+		}
+		attemptURL, baseURL, headers := preparedURL, req.BaseURL, req.Headers
+		var ep *poolEndpoint
+		if req.Pool != nil {
+			ep = req.Pool.pick(tried)
+			tried = append(tried, ep)
+			epURL, err := requestURL(ep.URL, req)
+			if err != nil {
+				req.Breaker.record(generation, outcomeIgnored)
+				return res, err
+			}
+			attemptURL, baseURL, headers = epURL, ep.URL, ep.headers(req.Headers)
+		}
+		res.attempts++
+		resp, bodyBytes, err := do(ctx, req.Client, attemptURL.String(), headers)
+		o := attemptOutcome(ctx, resp, err)
+		req.Breaker.record(generation, o)
+		req.Pool.record(ep, o, req.Client, req.Headers)
+		if err == nil {
+			res.resp, res.body, res.path = resp, bodyBytes, attemptURL.Path
+			res.url, res.headers, res.target = attemptURL, headers, target(baseURL, headers)
+		}
+		if o == outcomeFailure && ctx.Err() == nil && req.Pool.canFailOver(tried) {
+			continue
+		}
+		retries++
+		if retries >= maxAttempts {
+			return res, err
+		}
+		var delay time.Duration
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return res, err
+			}
+			delay = req.Retry.backoff(retries)
+		case req.Retry.isRetryableStatus(resp.StatusCode):
+			var ok bool
+			if delay, ok = retryAfter(resp.Header, time.Now()); ok {
+				delay = req.Retry.capRetryAfter(delay)
+			} else {
+				delay = req.Retry.backoff(retries)
+			}
+		default:
+			return res, nil
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return res, err
+		}
+		tried = nil
+	}
+	return res, nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, preparedURL.String(), nil)
+func do(ctx context.Context, hc client.HttpClient, rawURL string, headers map[string]string) (*http.Response, []byte, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	for hk, hv := range headers {
-		req.Header.Set(hk, hv)
+		httpReq.Header.Set(hk, hv)
 	}
 
-	resp, err := client.Do(req)
+	resp, err := hc.Do(httpReq)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
+	defer resp.Body.Close()
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return err
-	}
-	err = json.Unmarshal(bodyBytes, response)
-	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	if resp.StatusCode != 200 {
-		return response.GetError()
-	}
-	return nil
+	return resp, bodyBytes, nil
 }
 
 func Bool(v bool) *bool {
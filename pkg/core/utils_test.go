@@ -2,7 +2,10 @@ package core_test
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 
 	"github.com/what3words/w3w-go-wrapper/pkg/core"
@@ -15,11 +18,25 @@ func (fk FakeResponse) GetError() error {
 }
 
 func TestMakeRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/json" {
+			t.Errorf("ERROR: Expected path /json, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("random") != "123" {
+			t.Errorf("ERROR: Expected query parameter random=123, got %q", r.URL.RawQuery)
+		}
+		if r.Header.Get("accept") != "application/json" {
+			t.Errorf("ERROR: Expected accept header, got %q", r.Header.Get("accept"))
+		}
+		w.Write([]byte(`{"slideshow":{"title":"Sample Slide Show"}}`))
+	}))
+	defer srv.Close()
+
 	var fk FakeResponse
 	err := core.MakeGetRequest(
 		context.Background(),
 		http.DefaultClient,
-		"https://httpbin.org",
+		srv.URL,
 		map[string]string{
 			"random": "123",
 		},
@@ -32,4 +49,31 @@ func TestMakeRequest(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ERROR: Got error %v", err)
 	}
+	if _, ok := fk["slideshow"]; !ok {
+		t.Fatalf("ERROR: Expected the response to be decoded, got %v", fk)
+	}
+}
+
+func TestGetEmptyBody(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer srv.Close()
+	req := core.Request{Client: http.DefaultClient, BaseURL: srv.URL, Paths: []string{"report"}}
+
+	fk := FakeResponse{"kept": true}
+	if err := core.Get(context.Background(), req, &fk); err != nil {
+		t.Fatalf("ERROR: Expected an empty 200 response to succeed, got %v", err)
+	}
+	if len(fk) != 1 {
+		t.Fatalf("ERROR: Expected the response to be left unchanged, got %v", fk)
+	}
+
+	status.Store(http.StatusBadRequest)
+	var httpErr *core.HTTPError
+	if err := core.Get(context.Background(), req, &fk); !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("ERROR: Expected an HTTPError for an empty 400 response, got %v", err)
+	}
 }
@@ -0,0 +1,93 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// FieldError describes a problem with a single field of a request option.
+type FieldError struct {
+	// Field is the path of the invalid field, for example `ClipToPolygon[2].Lat`.
+	Field string
+	// Message describes why the field is invalid.
+	Message string
+}
+
+func (fe FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", fe.Field, fe.Message)
+}
+
+// ValidationError is returned when request options fail client side
+// validation. It lists every field problem found, not only the first one.
+//
+// Example inferance:
+//
+//	var validationErr *core.ValidationError
+//	if errors.As(err, &validationErr) {
+//		for _, fieldErr := range validationErr.Errors {
+//			fmt.Println(fieldErr.Field, fieldErr.Message)
+//		}
+//	}
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (ve *ValidationError) Error() string {
+	msgs := make([]string, 0, len(ve.Errors))
+	for _, fe := range ve.Errors {
+		msgs = append(msgs, fe.Error())
+	}
+	return fmt.Sprintf("validation: %s", strings.Join(msgs, "; "))
+}
+
+// Add records a problem with the given field.
+func (ve *ValidationError) Add(field, format string, args ...any) {
+	ve.Errors = append(ve.Errors, FieldError{
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// Merge records the problems of a nested ValidationError, prefixing their
+// field names with `field`. Any other non nil error is recorded as a problem
+// with the field itself.
+func (ve *ValidationError) Merge(field string, err error) {
+	if err == nil {
+		return
+	}
+	var nested *ValidationError
+	if !errors.As(err, &nested) {
+		ve.Add(field, "%v", err)
+		return
+	}
+	for _, fe := range nested.Errors {
+		if field != "" {
+			fe.Field = fmt.Sprintf("%s.%s", field, fe.Field)
+		}
+		ve.Errors = append(ve.Errors, fe)
+	}
+}
+
+// Err returns the ValidationError if any problem was recorded, nil otherwise.
+func (ve *ValidationError) Err() error {
+	if len(ve.Errors) == 0 {
+		return nil
+	}
+	return ve
+}
+
+// Validate checks the latitude is a number between -90 and 90 and the
+// longitude is a finite number. Longitudes are allowed to wrap around
+// the 180 line, so they are not bounded.
+func (c Coordinates) Validate() error {
+	var ve ValidationError
+	if math.IsNaN(c.Lat) || c.Lat < -90 || c.Lat > 90 {
+		ve.Add("Lat", "must be between -90 and 90, got %v", c.Lat)
+	}
+	if math.IsNaN(c.Lng) || math.IsInf(c.Lng, 0) {
+		ve.Add("Lng", "must be a finite number, got %v", c.Lng)
+	}
+	return ve.Err()
+}
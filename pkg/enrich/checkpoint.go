@@ -0,0 +1,101 @@
+package enrich
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+)
+
+// checkpointEntry records the result of a finished row.
+type checkpointEntry struct {
+	Row int `json:"row"`
+	// Input identifies the input of the row, so that entries are only
+	// reused for the same input.
+	Input        string   `json:"input"`
+	Words        string   `json:"words,omitempty"`
+	Lat          *float64 `json:"lat,omitempty"`
+	Lng          *float64 `json:"lng,omitempty"`
+	Country      string   `json:"country,omitempty"`
+	NearestPlace string   `json:"nearestPlace,omitempty"`
+	Error        string   `json:"error,omitempty"`
+}
+
+// checkpoint is a JSON Lines file holding an entry per finished row,
+// appended to as rows finish so that it survives interruptions.
+type checkpoint struct {
+	mu      sync.Mutex
+	file    *os.File
+	entries map[int]checkpointEntry
+}
+
+// openCheckpoint loads the entries of the checkpoint file at path,
+// creating it if it does not exist.
+func openCheckpoint(path string) (*checkpoint, error) {
+	cp := &checkpoint{entries: make(map[int]checkpointEntry)}
+	f, err := os.Open(path)
+	switch {
+	case err == nil:
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(nil, 1<<20)
+		for scanner.Scan() {
+			var entry checkpointEntry
+			// A partially written last line, left by an interruption,
+			// is ignored.
+			if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+				cp.entries[entry.Row] = entry
+			}
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	}
+	cp.file, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	// Start on a new line in case the last line was partially written.
+	if _, err := cp.file.WriteString("\n"); err != nil {
+		cp.file.Close()
+		return nil, err
+	}
+	return cp, nil
+}
+
+// lookup returns the entry of the row if it was finished with the same input.
+func (cp *checkpoint) lookup(row int, input string) (checkpointEntry, bool) {
+	if cp == nil {
+		return checkpointEntry{}, false
+	}
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	entry, ok := cp.entries[row]
+	return entry, ok && entry.Input == input
+}
+
+// record appends the entry to the checkpoint file.
+func (cp *checkpoint) record(entry checkpointEntry) error {
+	if cp == nil {
+		return nil
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	cp.entries[entry.Row] = entry
+	_, err = cp.file.Write(append(line, '\n'))
+	return err
+}
+
+func (cp *checkpoint) close() error {
+	if cp == nil {
+		return nil
+	}
+	return cp.file.Close()
+}
@@ -0,0 +1,396 @@
+// Package enrich adds what3words data to files of coordinates or three word
+// addresses, such as CSV exports handed over by analysts.
+//
+// An Enricher reads rows from CSV or JSON Lines, converts the input columns
+// of each row using the v3 API, and writes every row to the output with
+// additional columns holding the three word address or the coordinates,
+// the country, the nearest place and, when the row could not be converted,
+// the error. Rows are converted concurrently using the batch helpers of
+// the v3 package, and written in input order as soon as they are converted.
+//
+// Example usage:
+//
+//	enricher := enrich.New(svc.V3(),
+//	    enrich.WithCoordinateColumns("latitude", "longitude"),
+//	    enrich.WithCheckpoint("sites.checkpoint"),
+//	    enrich.WithBatchOptions(v3.WithBatchConcurrency(16)),
+//	)
+//	stats, err := enricher.Run(ctx, input, output)
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+// errBatchStopped is returned when the batch stops before converting
+// every row without reporting why.
+var errBatchStopped = errors.New("enrich: batch stopped before converting every row")
+
+// Stats summarises a run of an Enricher.
+type Stats struct {
+	// Rows is the number of rows written to the output.
+	Rows int
+	// Converted is the number of rows sent to the API.
+	Converted int
+	// Resumed is the number of rows taken from the checkpoint file
+	// without calling the API.
+	Resumed int
+	// Failed is the number of rows written with an error.
+	Failed int
+}
+
+// Enricher adds what3words columns to rows read from CSV or JSON Lines.
+type Enricher struct {
+	api         v3.API
+	format      Format
+	latColumn   string
+	lngColumn   string
+	wordsColumn string
+	prefix      string
+	opts        *v3.ConvertAPIOpts
+	batchOpts   []v3.BatchOption
+	isFatal     func(error) bool
+	checkpoint  string
+}
+
+// Option configures an Enricher.
+type Option func(*Enricher)
+
+// WithFormat sets the format of both the input and the output. Defaults to CSV.
+func WithFormat(format Format) Option {
+	return func(e *Enricher) {
+		e.format = format
+	}
+}
+
+// WithCoordinateColumns converts the coordinates held in the given latitude
+// and longitude columns to three word addresses. This is the default, using
+// the `lat` and `lng` columns.
+func WithCoordinateColumns(lat, lng string) Option {
+	return func(e *Enricher) {
+		e.latColumn, e.lngColumn, e.wordsColumn = lat, lng, ""
+	}
+}
+
+// WithWordsColumn converts the three word addresses held in the given
+// column to coordinates.
+func WithWordsColumn(words string) Option {
+	return func(e *Enricher) {
+		e.wordsColumn = words
+	}
+}
+
+// WithColumnPrefix sets the prefix of the columns added to the output,
+// which are named `words` (or `lat` and `lng`), `country`, `nearest_place`
+// and `error`. Defaults to `w3w_`.
+func WithColumnPrefix(prefix string) Option {
+	return func(e *Enricher) {
+		e.prefix = prefix
+	}
+}
+
+// WithConvertOpts sets the options, such as the language, passed to
+// every conversion.
+func WithConvertOpts(opts *v3.ConvertAPIOpts) Option {
+	return func(e *Enricher) {
+		e.opts = opts
+	}
+}
+
+// WithBatchOptions sets the options of the underlying batch, such as its
+// concurrency or a progress callback. Use WithAbortOn rather than
+// v3.WithBatchAbortOn to decide which errors abort the run.
+func WithBatchOptions(opts ...v3.BatchOption) Option {
+	return func(e *Enricher) {
+		e.batchOpts = append(e.batchOpts, opts...)
+	}
+}
+
+// WithAbortOn sets the function deciding whether an error is fatal, aborting
+// the run. By default runs are aborted on authentication errors and when the
+// quota is exceeded.
+func WithAbortOn(isFatal func(error) bool) Option {
+	return func(e *Enricher) {
+		e.isFatal = isFatal
+	}
+}
+
+// WithCheckpoint records every finished row in the checkpoint file at path.
+// When a run is interrupted, for example by a crash or a fatal error, running
+// it again with the same input and checkpoint writes the rows finished
+// previously from the checkpoint without calling the API again. Rows which
+// failed with a transient error, such as a timeout, are converted again.
+func WithCheckpoint(path string) Option {
+	return func(e *Enricher) {
+		e.checkpoint = path
+	}
+}
+
+// New creates an Enricher converting rows with the given API.
+func New(api v3.API, opts ...Option) *Enricher {
+	e := &Enricher{
+		api:       api,
+		format:    CSV,
+		latColumn: "lat",
+		lngColumn: "lng",
+		prefix:    "w3w_",
+		isFatal: func(err error) bool {
+			return v3.IsAuthError(err) || v3.IsQuotaError(err)
+		},
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Run reads every row from r and writes it to w with the what3words columns
+// added. Rows which cannot be converted, for example because of invalid
+// coordinates, are written with an error and do not stop the run. Run stops
+// on the first fatal error, after writing the rows finished so far, and
+// returns it. It also stops on input which cannot be read, such as a CSV
+// row with more fields than the header. Stats are returned even when an
+// error is returned.
+func (e *Enricher) Run(ctx context.Context, r io.Reader, w io.Writer) (Stats, error) {
+	if e.wordsColumn != "" {
+		return run(ctx, e, r, w, []string{"lat", "lng"}, e.parseWords, func(ctx context.Context, in <-chan string, batchOpts []v3.BatchOption) <-chan v3.BatchResult[string] {
+			return v3.ConvertToCoordinatesStream(ctx, e.api, in, e.opts, batchOpts...)
+		})
+	}
+	return run(ctx, e, r, w, []string{"words"}, e.parseCoordinates, func(ctx context.Context, in <-chan core.Coordinates, batchOpts []v3.BatchOption) <-chan v3.BatchResult[core.Coordinates] {
+		return v3.ConvertTo3waStream(ctx, e.api, in, e.opts, batchOpts...)
+	})
+}
+
+// parseWords returns the words of the row, and the input identifying it.
+func (e *Enricher) parseWords(rd *reader, r row) (string, string, error) {
+	words, ok := r.value(rd.columns, e.wordsColumn)
+	words = strings.TrimSpace(words)
+	if !ok || words == "" {
+		return "", words, fmt.Errorf("missing %s", e.wordsColumn)
+	}
+	return words, words, nil
+}
+
+// parseCoordinates returns the coordinates of the row, and the input
+// identifying it.
+func (e *Enricher) parseCoordinates(rd *reader, r row) (core.Coordinates, string, error) {
+	lat, latOK := r.value(rd.columns, e.latColumn)
+	lng, lngOK := r.value(rd.columns, e.lngColumn)
+	lat, lng = strings.TrimSpace(lat), strings.TrimSpace(lng)
+	input := lat + "," + lng
+	if !latOK || lat == "" {
+		return core.Coordinates{}, input, fmt.Errorf("missing %s", e.latColumn)
+	}
+	if !lngOK || lng == "" {
+		return core.Coordinates{}, input, fmt.Errorf("missing %s", e.lngColumn)
+	}
+	var coordinates core.Coordinates
+	var err error
+	if coordinates.Lat, err = strconv.ParseFloat(lat, 64); err != nil {
+		return core.Coordinates{}, input, fmt.Errorf("invalid %s %q", e.latColumn, lat)
+	}
+	if coordinates.Lng, err = strconv.ParseFloat(lng, 64); err != nil {
+		return core.Coordinates{}, input, fmt.Errorf("invalid %s %q", e.lngColumn, lng)
+	}
+	return coordinates, input, nil
+}
+
+// pending is a row read from the input, waiting to be written.
+type pending struct {
+	row   row
+	input string
+	// entry holds the result of the row when it is already known, because
+	// it was resumed from the checkpoint or could not be parsed.
+	entry *checkpointEntry
+	// resumed is true when entry was taken from the checkpoint.
+	resumed bool
+}
+
+func run[In any](
+	ctx context.Context,
+	e *Enricher,
+	r io.Reader,
+	w io.Writer,
+	resultColumns []string,
+	parse func(*reader, row) (In, string, error),
+	stream func(context.Context, <-chan In, []v3.BatchOption) <-chan v3.BatchResult[In],
+) (Stats, error) {
+	var stats Stats
+	rd, err := newReader(r, e.format)
+	if err != nil {
+		return stats, err
+	}
+	columns := make([]string, 0, len(resultColumns)+3)
+	for _, column := range append(resultColumns, "country", "nearest_place", "error") {
+		columns = append(columns, e.prefix+column)
+	}
+	wr, err := newWriter(w, e.format, rd.header, columns)
+	if err != nil {
+		return stats, err
+	}
+	var cp *checkpoint
+	if e.checkpoint != "" {
+		if cp, err = openCheckpoint(e.checkpoint); err != nil {
+			return stats, err
+		}
+		defer cp.close()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var fatal error
+	batchOpts := append(append([]v3.BatchOption{}, e.batchOpts...), v3.WithBatchAbortOn(e.isFatal))
+
+	// The reader sends every row to rows in input order, and the rows to
+	// convert to inputs. Results are sent by the batch in the same order.
+	rows := make(chan pending, 1024)
+	inputs := make(chan In)
+	var readErr error
+	go func() {
+		defer close(rows)
+		defer close(inputs)
+		for {
+			r, err := rd.read()
+			if err != nil {
+				if !isEOF(err) {
+					readErr = err
+				}
+				return
+			}
+			p := pending{row: r}
+			value, input, parseErr := parse(rd, r)
+			p.input = input
+			if entry, ok := cp.lookup(r.index, input); ok {
+				p.entry, p.resumed = &entry, true
+			} else if parseErr != nil {
+				p.entry = &checkpointEntry{Row: r.index, Input: input, Error: parseErr.Error()}
+			}
+			select {
+			case rows <- p:
+			case <-ctx.Done():
+				return
+			}
+			if p.entry != nil {
+				continue
+			}
+			select {
+			case inputs <- value:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	results := stream(ctx, inputs, batchOpts)
+	defer func() {
+		// Drain the batch so that it stops once cancelled.
+		cancel()
+		for range results {
+		}
+	}()
+
+	for p := range rows {
+		entry := p.entry
+		if entry == nil {
+			result, ok := <-results
+			if !ok {
+				// The batch only stops before converting every input once
+				// ctx is done, the reader may still be running.
+				if fatal = ctx.Err(); fatal == nil {
+					fatal = errBatchStopped
+				}
+				break
+			}
+			if errors.Is(result.Err, v3.ErrBatchAborted) {
+				// Calls in flight when the batch was aborted come before
+				// the result holding the fatal error.
+				fatal = result.Err
+				for result := range results {
+					if e.isFatal != nil && e.isFatal(result.Err) {
+						fatal = result.Err
+					}
+				}
+				break
+			}
+			if result.Err != nil && e.isFatal != nil && e.isFatal(result.Err) {
+				fatal = result.Err
+				break
+			}
+			entry = resultEntry(p, result.Response, result.Err)
+			if result.Err == nil || v3.IsInputError(result.Err) {
+				if err := cp.record(*entry); err != nil {
+					return stats, err
+				}
+			}
+			stats.Converted++
+		} else if p.resumed {
+			stats.Resumed++
+		} else if err := cp.record(*entry); err != nil {
+			return stats, err
+		}
+		if err := wr.write(p.row, entryValues(entry, resultColumns)); err != nil {
+			return stats, err
+		}
+		stats.Rows++
+		if entry.Error != "" {
+			stats.Failed++
+		}
+	}
+	if err := wr.flush(); err != nil {
+		return stats, err
+	}
+	if fatal != nil {
+		return stats, fatal
+	}
+	if err := ctx.Err(); err != nil {
+		return stats, err
+	}
+	// Every row was received, so the reader has returned.
+	return stats, readErr
+}
+
+func resultEntry(p pending, resp *v3.ConvertAPIJsonResponse, err error) *checkpointEntry {
+	entry := &checkpointEntry{Row: p.row.index, Input: p.input}
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+	entry.Words = resp.Words
+	entry.Lat, entry.Lng = &resp.Coordinates.Lat, &resp.Coordinates.Lng
+	entry.Country = resp.Country
+	entry.NearestPlace = resp.NearestPlace
+	return entry
+}
+
+// entryValues returns the values of the output columns for the entry.
+func entryValues(entry *checkpointEntry, resultColumns []string) []any {
+	values := make([]any, 0, len(resultColumns)+3)
+	for _, column := range resultColumns {
+		switch {
+		case column == "words" && entry.Words != "":
+			values = append(values, entry.Words)
+		case column == "lat" && entry.Lat != nil && entry.Error == "":
+			values = append(values, *entry.Lat)
+		case column == "lng" && entry.Lng != nil && entry.Error == "":
+			values = append(values, *entry.Lng)
+		default:
+			values = append(values, nil)
+		}
+	}
+	for _, value := range []string{entry.Country, entry.NearestPlace, entry.Error} {
+		if value == "" {
+			values = append(values, nil)
+		} else {
+			values = append(values, value)
+		}
+	}
+	return values
+}
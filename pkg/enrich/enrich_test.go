@@ -0,0 +1,211 @@
+package enrich_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/enrich"
+)
+
+// enrichServer converts coordinates to their query parameter as words, and words to
+// the coordinates 1,2, failing with InvalidKey while revoked is set.
+func enrichServer(t *testing.T, calls *atomic.Int32, revoked *atomic.Bool) v3.API {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if revoked != nil && revoked.Load() {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"code":"InvalidKey","message":"Authentication failed; invalid API key"}}`))
+			return
+		}
+		words := r.URL.Query().Get("words")
+		if coordinates := r.URL.Query().Get("coordinates"); coordinates != "" {
+			words = coordinates
+		}
+		if words == "bad.words.here" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":"BadWords","message":"Invalid words"}}`))
+			return
+		}
+		fmt.Fprintf(w, `{"words":%q,"country":"GB","nearestPlace":"London","coordinates":{"lat":1,"lng":2}}`, words)
+	}))
+	t.Cleanup(srv.Close)
+	return v3.NewAPI("key", v3.WithCustomBaseURL(srv.URL))
+}
+
+func TestEnrichCSVCoordinates(t *testing.T) {
+	var calls atomic.Int32
+	api := enrichServer(t, &calls, nil)
+	input := "id,latitude,longitude\n1,51.5,-0.1\n2,abc,0\n3,95,0\n4,10,20\n"
+
+	var out bytes.Buffer
+	stats, err := enrich.New(api, enrich.WithCoordinateColumns("latitude", "longitude")).
+		Run(context.Background(), strings.NewReader(input), &out)
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	expectedHeader := "id,latitude,longitude,w3w_words,w3w_country,w3w_nearest_place,w3w_error"
+	if strings.Join(records[0], ",") != expectedHeader {
+		t.Fatalf("ERROR: Expected header %s, got %v", expectedHeader, records[0])
+	}
+	if len(records) != 5 {
+		t.Fatalf("ERROR: Expected 4 rows, got %d", len(records)-1)
+	}
+	if records[1][3] != "51.500000,-0.100000" || records[1][4] != "GB" || records[1][5] != "London" || records[1][6] != "" {
+		t.Fatalf("ERROR: Expected converted row, got %v", records[1])
+	}
+	if records[2][3] != "" || records[2][6] != `invalid latitude "abc"` {
+		t.Fatalf("ERROR: Expected parse error, got %v", records[2])
+	}
+	if records[3][6] == "" {
+		t.Fatalf("ERROR: Expected validation error, got %v", records[3])
+	}
+	if records[4][0] != "4" || records[4][3] != "10.000000,20.000000" {
+		t.Fatalf("ERROR: Expected rows in input order, got %v", records[4])
+	}
+	if stats.Rows != 4 || stats.Failed != 2 || calls.Load() != 2 {
+		t.Fatalf("ERROR: Expected 4 rows, 2 failed and 2 requests, got %+v and %d requests", stats, calls.Load())
+	}
+}
+
+func TestEnrichJSONLWords(t *testing.T) {
+	var calls atomic.Int32
+	api := enrichServer(t, &calls, nil)
+	input := `{"id":1,"address":"filled.count.soap"}` + "\n\n" + `{"id":2,"address":"bad.words.here"}` + "\n"
+
+	var out bytes.Buffer
+	_, err := enrich.New(api, enrich.WithFormat(enrich.JSONL), enrich.WithWordsColumn("address")).
+		Run(context.Background(), strings.NewReader(input), &out)
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	dec := json.NewDecoder(&out)
+	var first, second map[string]any
+	if err := dec.Decode(&first); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if err := dec.Decode(&second); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if first["id"] != float64(1) || first["w3w_lat"] != float64(1) || first["w3w_lng"] != float64(2) || first["w3w_error"] != nil {
+		t.Fatalf("ERROR: Expected coordinates, got %v", first)
+	}
+	if second["w3w_lat"] != nil || !strings.Contains(fmt.Sprint(second["w3w_error"]), "BadWords") {
+		t.Fatalf("ERROR: Expected BadWords error, got %v", second)
+	}
+}
+
+func TestEnrichResumesFromCheckpoint(t *testing.T) {
+	var calls atomic.Int32
+	var revoked atomic.Bool
+	api := enrichServer(t, &calls, &revoked)
+	checkpoint := filepath.Join(t.TempDir(), "run.checkpoint")
+
+	var input strings.Builder
+	input.WriteString("address\n")
+	for i := 0; i < 50; i++ {
+		if i == 3 {
+			input.WriteString("bad.words.here\n")
+			continue
+		}
+		fmt.Fprintf(&input, "word.number.w%d\n", i)
+	}
+	enricher := enrich.New(api,
+		enrich.WithWordsColumn("address"),
+		enrich.WithCheckpoint(checkpoint),
+		enrich.WithBatchOptions(v3.WithBatchConcurrency(1), v3.WithBatchProgress(func(done, total int) {
+			if done == 20 {
+				revoked.Store(true)
+			}
+		})),
+	)
+
+	var out bytes.Buffer
+	stats, err := enricher.Run(context.Background(), strings.NewReader(input.String()), &out)
+	if !v3.IsAuthError(err) {
+		t.Fatalf("ERROR: Expected the run to abort with an auth error, got %v", err)
+	}
+	if stats.Rows < 20 || stats.Rows >= 50 {
+		t.Fatalf("ERROR: Expected a partial run, got %+v", stats)
+	}
+	firstRun := calls.Load()
+
+	revoked.Store(false)
+	out.Reset()
+	stats, err = enrich.New(api, enrich.WithWordsColumn("address"), enrich.WithCheckpoint(checkpoint)).
+		Run(context.Background(), strings.NewReader(input.String()), &out)
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if stats.Rows != 50 || stats.Resumed < 20 || stats.Failed != 1 {
+		t.Fatalf("ERROR: Expected a complete run resuming finished rows, got %+v", stats)
+	}
+	if int(calls.Load()-firstRun) != stats.Converted || stats.Converted+stats.Resumed != 50 {
+		t.Fatalf("ERROR: Expected only unfinished rows to be queried, got %d requests and %+v", calls.Load()-firstRun, stats)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != 51 {
+		t.Fatalf("ERROR: Expected 50 rows and a header, got %d lines", lines)
+	}
+}
+
+func TestEnrichRejectsWideCSVRows(t *testing.T) {
+	var calls atomic.Int32
+	api := enrichServer(t, &calls, nil)
+	input := "id,lat,lng\n1,51.5,-0.1\n2,10,20,extra\n3,10,20\n"
+
+	var out bytes.Buffer
+	stats, err := enrich.New(api).Run(context.Background(), strings.NewReader(input), &out)
+	if err == nil || !strings.Contains(err.Error(), "line 3: 4 fields, the header has 3") {
+		t.Fatalf("ERROR: Expected the row wider than the header to be rejected, got %v", err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if stats.Rows != 1 || len(records) != 2 || records[1][4] != "GB" {
+		t.Fatalf("ERROR: Expected only the row before it to be written, got %+v and %v", stats, records)
+	}
+}
+
+func TestEnrichCancelled(t *testing.T) {
+	var calls atomic.Int32
+	api := enrichServer(t, &calls, nil)
+	var input strings.Builder
+	input.WriteString("address\n")
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(&input, "word.number.w%d\n", i)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	enricher := enrich.New(api,
+		enrich.WithWordsColumn("address"),
+		enrich.WithBatchOptions(v3.WithBatchConcurrency(1), v3.WithBatchProgress(func(done, total int) {
+			if done == 5 {
+				cancel()
+			}
+		})),
+	)
+
+	var out bytes.Buffer
+	stats, err := enricher.Run(ctx, strings.NewReader(input.String()), &out)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("ERROR: Expected context.Canceled, got %v", err)
+	}
+	if stats.Rows >= 50 {
+		t.Fatalf("ERROR: Expected a partial run, got %+v", stats)
+	}
+}
@@ -0,0 +1,203 @@
+package enrich
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Format of the input and output of an Enricher.
+type Format int
+
+const (
+	// CSV reads and writes comma separated values, the first record
+	// of the input holding the column names.
+	CSV Format = iota
+	// JSONL reads and writes JSON Lines, one JSON object per line whose
+	// fields are the columns.
+	JSONL
+)
+
+// row is a single record of the input.
+type row struct {
+	// index of the row in the input, starting at 0 for the first data row.
+	index  int
+	fields []string
+	object map[string]any
+}
+
+// value returns the value of the column as a string.
+func (r row) value(columns map[string]int, column string) (string, bool) {
+	if r.object != nil {
+		v, ok := r.object[column]
+		if !ok || v == nil {
+			return "", false
+		}
+		if s, ok := v.(string); ok {
+			return s, true
+		}
+		return fmt.Sprint(v), true
+	}
+	i, ok := columns[column]
+	if !ok || i >= len(r.fields) {
+		return "", false
+	}
+	return r.fields[i], true
+}
+
+// reader reads rows from the input in either format.
+type reader struct {
+	format Format
+	csv    *csv.Reader
+	lines  *bufio.Reader
+	// columns maps CSV column names to their position.
+	columns map[string]int
+	header  []string
+	next    int
+}
+
+func newReader(r io.Reader, format Format) (*reader, error) {
+	rd := &reader{format: format}
+	if format == JSONL {
+		rd.lines = bufio.NewReader(r)
+		return rd, nil
+	}
+	rd.csv = csv.NewReader(r)
+	rd.csv.FieldsPerRecord = -1
+	header, err := rd.csv.Read()
+	if err != nil {
+		return nil, fmt.Errorf("enrich: reading CSV header: %w", err)
+	}
+	rd.header = header
+	rd.columns = make(map[string]int, len(header))
+	for i, column := range header {
+		rd.columns[column] = i
+	}
+	return rd, nil
+}
+
+// read returns the next row, or io.EOF at the end of the input.
+func (rd *reader) read() (row, error) {
+	if rd.format == JSONL {
+		for {
+			line, err := rd.lines.ReadBytes('\n')
+			if len(bytes.TrimSpace(line)) == 0 {
+				if err != nil {
+					return row{}, err
+				}
+				continue
+			}
+			dec := json.NewDecoder(bytes.NewReader(line))
+			dec.UseNumber()
+			object := make(map[string]any)
+			if decErr := dec.Decode(&object); decErr != nil {
+				return row{}, fmt.Errorf("enrich: line %d: %w", rd.next+1, decErr)
+			}
+			if object == nil {
+				return row{}, fmt.Errorf("enrich: line %d: not a JSON object", rd.next+1)
+			}
+			r := row{index: rd.next, object: object}
+			rd.next++
+			return r, nil
+		}
+	}
+	fields, err := rd.csv.Read()
+	if err != nil {
+		return row{}, err
+	}
+	if len(fields) > len(rd.header) {
+		// Extra fields would shift the output columns.
+		line, _ := rd.csv.FieldPos(0)
+		return row{}, fmt.Errorf("enrich: line %d: %d fields, the header has %d", line, len(fields), len(rd.header))
+	}
+	r := row{index: rd.next, fields: fields}
+	rd.next++
+	return r, nil
+}
+
+// writer writes enriched rows in the format of the input.
+type writer struct {
+	format  Format
+	csv     *csv.Writer
+	json    *json.Encoder
+	buf     *bufio.Writer
+	columns []string
+	// width is the number of input columns of CSV records, shorter
+	// records are padded so that output columns line up.
+	width   int
+	written int
+}
+
+func newWriter(w io.Writer, format Format, header, columns []string) (*writer, error) {
+	wr := &writer{format: format, columns: columns, width: len(header)}
+	if format == JSONL {
+		wr.buf = bufio.NewWriter(w)
+		wr.json = json.NewEncoder(wr.buf)
+		wr.json.SetEscapeHTML(false)
+		return wr, nil
+	}
+	wr.csv = csv.NewWriter(w)
+	if err := wr.csv.Write(append(append([]string{}, header...), columns...)); err != nil {
+		return nil, err
+	}
+	return wr, nil
+}
+
+// write writes the row followed by the values of the output columns,
+// which are nil when empty.
+func (wr *writer) write(r row, values []any) error {
+	if wr.format == JSONL {
+		for i, column := range wr.columns {
+			r.object[column] = values[i]
+		}
+		if err := wr.json.Encode(r.object); err != nil {
+			return err
+		}
+	} else {
+		record := append([]string{}, r.fields...)
+		for len(record) < wr.width {
+			record = append(record, "")
+		}
+		for _, value := range values {
+			record = append(record, csvValue(value))
+		}
+		if err := wr.csv.Write(record); err != nil {
+			return err
+		}
+	}
+	wr.written++
+	if wr.written%100 == 0 {
+		return wr.flush()
+	}
+	return nil
+}
+
+func (wr *writer) flush() error {
+	if wr.format == JSONL {
+		return wr.buf.Flush()
+	}
+	wr.csv.Flush()
+	return wr.csv.Error()
+}
+
+func csvValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// isEOF reports whether err marks the end of the input.
+func isEOF(err error) bool {
+	return errors.Is(err, io.EOF)
+}
@@ -0,0 +1,190 @@
+// Package w3wfake provides FakeService, an in-memory implementation of
+// w3wgowrapper.Service for tests.
+//
+// Results are scripted per method and input, calls are recorded and can be
+// asserted on:
+//
+//	svc := w3wfake.New()
+//	svc.OnIsValid3wa("filled.count.soap").Return(true)
+//	svc.API.OnConvertToCoordinates().ReturnError(v3.ErrBadWords)
+//
+//	// Exercise the code under test with svc.
+//
+//	svc.AssertCalled(t, w3wfake.MethodIsValid3wa, "filled.count.soap")
+//
+// Methods without scripted result behave as the real service: the regular
+// expression methods match their input locally and IsValid3wa calls
+// AutoSuggest on the fake API.
+package w3wfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	w3w "github.com/what3words/w3w-go-wrapper"
+	"github.com/what3words/w3w-go-wrapper/internal/fake"
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/apis/v3/v3fake"
+)
+
+// Methods of w3wgowrapper.Service that can be scripted and asserted on.
+const (
+	MethodFindPossible3wa = "FindPossible3wa"
+	MethodIsPossible3wa   = "IsPossible3wa"
+	MethodDidYouMean      = "DidYouMean"
+	MethodIsValid3wa      = "IsValid3wa"
+)
+
+// Call is a call made to a FakeService. Input holds the string passed to
+// the method.
+type Call = fake.Call
+
+// FakeService is an in-memory w3wgowrapper.Service. It is safe for
+// concurrent use.
+type FakeService struct {
+	// API is returned by V3 and used by IsValid3wa when it is not scripted.
+	API *v3fake.FakeAPI
+
+	reg  *fake.Registry
+	real w3w.Service
+}
+
+var _ w3w.Service = (*FakeService)(nil)
+
+// New returns a FakeService without scripted result, backed by a new
+// v3fake.FakeAPI.
+func New() *FakeService {
+	api := v3fake.New()
+	return &FakeService{
+		API:  api,
+		reg:  fake.NewRegistry(),
+		real: w3w.NewService("", w3w.WithV3API(api)),
+	}
+}
+
+// Stub scripts the results of a method for some inputs. Results are returned
+// in the order they are added, the last one being repeated.
+type Stub[T any] struct {
+	reg    *fake.Registry
+	method string
+	inputs []any
+	delay  time.Duration
+}
+
+func newStub[T any](reg *fake.Registry, method string, inputs []string) *Stub[T] {
+	s := &Stub[T]{reg: reg, method: method}
+	for _, input := range inputs {
+		s.inputs = append(s.inputs, input)
+	}
+	return s
+}
+
+// Return adds a result.
+func (s *Stub[T]) Return(result T) *Stub[T] {
+	s.reg.Add(s.method, s.inputs, fake.Result{Value: result, Delay: s.delay})
+	return s
+}
+
+// After delays the results added next by d.
+func (s *Stub[T]) After(d time.Duration) *Stub[T] {
+	s.delay = d
+	return s
+}
+
+// OnFindPossible3wa scripts FindPossible3wa for the inputs, or for any input
+// when none is given.
+func (f *FakeService) OnFindPossible3wa(inputs ...string) *Stub[[]string] {
+	return newStub[[]string](f.reg, MethodFindPossible3wa, inputs)
+}
+
+// OnIsPossible3wa scripts IsPossible3wa for the inputs, or for any input
+// when none is given.
+func (f *FakeService) OnIsPossible3wa(inputs ...string) *Stub[bool] {
+	return newStub[bool](f.reg, MethodIsPossible3wa, inputs)
+}
+
+// OnDidYouMean scripts DidYouMean for the inputs, or for any input when none
+// is given.
+func (f *FakeService) OnDidYouMean(inputs ...string) *Stub[bool] {
+	return newStub[bool](f.reg, MethodDidYouMean, inputs)
+}
+
+// OnIsValid3wa scripts IsValid3wa for the inputs, or for any input when none
+// is given.
+func (f *FakeService) OnIsValid3wa(inputs ...string) *Stub[bool] {
+	return newStub[bool](f.reg, MethodIsValid3wa, inputs)
+}
+
+// SetLatency delays every call to the method by d, or every call to any
+// method when method is empty. The latency of the API is set on API.
+func (f *FakeService) SetLatency(method string, d time.Duration) {
+	f.reg.SetLatency(method, d)
+}
+
+// Reset forgets the scripted results, latencies and recorded calls of the
+// service and of its API.
+func (f *FakeService) Reset() {
+	f.reg.Reset()
+	f.API.Reset()
+}
+
+// Calls returns the calls made so far, in order, to the given methods or
+// to any method when none is given. Calls made to the API are recorded
+// on API.
+func (f *FakeService) Calls(methods ...string) []Call {
+	return f.reg.Calls(methods...)
+}
+
+// AssertCalled fails the test unless the method was called, with one of the
+// inputs when any is given.
+func (f *FakeService) AssertCalled(t testing.TB, method string, inputs ...any) {
+	t.Helper()
+	f.reg.AssertCalled(t, method, inputs...)
+}
+
+// AssertNotCalled fails the test if the method was called.
+func (f *FakeService) AssertNotCalled(t testing.TB, method string) {
+	t.Helper()
+	f.reg.AssertNotCalled(t, method)
+}
+
+// AssertCallCount fails the test unless the method was called n times.
+func (f *FakeService) AssertCallCount(t testing.TB, method string, n int) {
+	t.Helper()
+	f.reg.AssertCallCount(t, method, n)
+}
+
+func (f *FakeService) V3() v3.API {
+	return f.API
+}
+
+func (f *FakeService) FindPossible3wa(input string) []string {
+	if value, scripted, _ := f.reg.Call(context.Background(), MethodFindPossible3wa, input, nil); scripted {
+		found, _ := value.([]string)
+		return found
+	}
+	return f.real.FindPossible3wa(input)
+}
+
+func (f *FakeService) IsPossible3wa(input string) bool {
+	if value, scripted, _ := f.reg.Call(context.Background(), MethodIsPossible3wa, input, nil); scripted {
+		return value == true
+	}
+	return f.real.IsPossible3wa(input)
+}
+
+func (f *FakeService) DidYouMean(input string) bool {
+	if value, scripted, _ := f.reg.Call(context.Background(), MethodDidYouMean, input, nil); scripted {
+		return value == true
+	}
+	return f.real.DidYouMean(input)
+}
+
+func (f *FakeService) IsValid3wa(ctx context.Context, input string) bool {
+	value, scripted, err := f.reg.Call(ctx, MethodIsValid3wa, input, nil)
+	if !scripted {
+		return f.real.IsValid3wa(ctx, input)
+	}
+	return err == nil && value == true
+}
@@ -0,0 +1,49 @@
+package w3wfake_test
+
+import (
+	"context"
+	"testing"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/apis/v3/v3fake"
+	"github.com/what3words/w3w-go-wrapper/pkg/w3wfake"
+)
+
+func TestFakeService(t *testing.T) {
+	svc := w3wfake.New()
+	ctx := context.Background()
+
+	svc.OnIsValid3wa("filled.count.soap").Return(true)
+	svc.OnDidYouMean().Return(false)
+	if !svc.IsValid3wa(ctx, "filled.count.soap") {
+		t.Fatal("ERROR: Expected the scripted result for filled.count.soap")
+	}
+	if svc.DidYouMean("filled-count-soap") {
+		t.Fatal("ERROR: Expected the scripted result for any input")
+	}
+	svc.API.AssertNotCalled(t, v3fake.MethodAutoSuggest)
+
+	if !svc.IsPossible3wa("index.home.raft") || svc.IsPossible3wa("index.home") {
+		t.Fatal("ERROR: Expected IsPossible3wa to match locally when not scripted")
+	}
+	if found := svc.FindPossible3wa("meet at index.home.raft"); len(found) != 1 || found[0] != "index.home.raft" {
+		t.Fatalf("ERROR: Expected FindPossible3wa to match locally when not scripted, got %v", found)
+	}
+
+	svc.API.OnAutoSuggest("index.home.raft").Return(&v3.AutoSuggestResponse{
+		Suggestions: []v3.AutoSuggestSuggestion{{Words: "index.home.raft"}},
+	})
+	if !svc.IsValid3wa(ctx, "index.home.raft") {
+		t.Fatal("ERROR: Expected IsValid3wa to use the fake API when not scripted")
+	}
+	if svc.IsValid3wa(ctx, "daring.lion.race") {
+		t.Fatal("ERROR: Expected IsValid3wa to be false when the fake API is not scripted")
+	}
+	svc.API.AssertCalled(t, v3fake.MethodAutoSuggest, "index.home.raft", "daring.lion.race")
+
+	svc.AssertCallCount(t, w3wfake.MethodIsValid3wa, 3)
+	svc.AssertCalled(t, w3wfake.MethodIsPossible3wa, "index.home")
+	if svc.V3() != svc.API {
+		t.Fatal("ERROR: Expected V3 to return the fake API")
+	}
+}
@@ -0,0 +1,156 @@
+package w3wtest
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+	"strings"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+// The fake grid divides the world in cells of CellSize degrees, roughly 3m
+// by 3m at the equator. Each cell is named by three words, each made of
+// five letters alternating consonants and vowels, such as "bacad.tisum.zoxer".
+// Neighbouring cells get unrelated words, as with the real grid.
+const (
+	// CellSize is the size in degrees of the sides of the cells of the fake grid.
+	CellSize = 0.000027
+
+	consonants = "bcdfghjklmnprstvwxyz"
+	vowels     = "aeiou"
+	// wordCount is the number of words of the form CVCVC.
+	wordCount = 20 * 5 * 20 * 5 * 20
+	// addressCount is the number of three word addresses.
+	addressCount = wordCount * wordCount * wordCount
+	// scramble is multiplied with the index of a cell, modulo addressCount,
+	// to spread neighbouring cells over unrelated words. It is coprime with
+	// addressCount, so that every cell gets its own address.
+	scramble = 2654435761
+)
+
+var (
+	latCells = int64(math.Ceil(180 / CellSize))
+	lngCells = int64(math.Ceil(360 / CellSize))
+	// unscramble is the inverse of scramble modulo addressCount.
+	unscramble = new(big.Int).ModInverse(big.NewInt(scramble), big.NewInt(addressCount)).Uint64()
+)
+
+// mulMod returns a*b modulo addressCount without overflowing.
+func mulMod(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return bits.Rem64(hi, lo, addressCount)
+}
+
+// word returns the word with the given index, below wordCount.
+func word(index uint64) string {
+	var b [5]byte
+	for i := 4; i >= 0; i-- {
+		letters := consonants
+		if i%2 == 1 {
+			letters = vowels
+		}
+		b[i] = letters[index%uint64(len(letters))]
+		index /= uint64(len(letters))
+	}
+	return string(b[:])
+}
+
+// wordIndex returns the index of the word, false if it is not a word of
+// the fake grid.
+func wordIndex(w string) (uint64, bool) {
+	if len(w) != 5 {
+		return 0, false
+	}
+	var index uint64
+	for i := 0; i < 5; i++ {
+		letters := consonants
+		if i%2 == 1 {
+			letters = vowels
+		}
+		pos := strings.IndexByte(letters, w[i])
+		if pos < 0 {
+			return 0, false
+		}
+		index = index*uint64(len(letters)) + uint64(pos)
+	}
+	return index, true
+}
+
+// Words returns the fake three word address of the cell holding the coordinates.
+// Longitudes are wrapped to the range [-180, 180).
+func Words(c core.Coordinates) string {
+	lat := math.Min(math.Max(c.Lat, -90), 90)
+	lng := math.Mod(c.Lng+180, 360)
+	if lng < 0 {
+		lng += 360
+	}
+	row := min(int64((lat+90)/CellSize), latCells-1)
+	col := min(int64(lng/CellSize), lngCells-1)
+	n := mulMod(uint64(row*lngCells+col), scramble)
+	return word(n/(wordCount*wordCount)) + "." + word(n/wordCount%wordCount) + "." + word(n%wordCount)
+}
+
+// Square returns the square of the cell named by the fake three word address,
+// false if no cell has this address.
+func Square(words string) (v3.Sqaure, bool) {
+	parts := strings.Split(strings.TrimPrefix(words, "///"), ".")
+	if len(parts) != 3 {
+		return v3.Sqaure{}, false
+	}
+	var n uint64
+	for _, part := range parts {
+		index, ok := wordIndex(strings.ToLower(part))
+		if !ok {
+			return v3.Sqaure{}, false
+		}
+		n = n*wordCount + index
+	}
+	cell := mulMod(n, unscramble)
+	if cell >= uint64(latCells*lngCells) {
+		return v3.Sqaure{}, false
+	}
+	row, col := int64(cell)/lngCells, int64(cell)%lngCells
+	return v3.Sqaure{
+		SouthWest: core.Coordinates{
+			Lat: round(float64(row)*CellSize - 90),
+			Lng: round(float64(col)*CellSize - 180),
+		},
+		NorthEast: core.Coordinates{
+			Lat: round(math.Min(float64(row+1)*CellSize-90, 90)),
+			Lng: round(math.Min(float64(col+1)*CellSize-180, 180)),
+		},
+	}, true
+}
+
+// Coordinates returns the centre of the cell named by the fake three word
+// address, false if no cell has this address.
+func Coordinates(words string) (core.Coordinates, bool) {
+	square, ok := Square(words)
+	if !ok {
+		return core.Coordinates{}, false
+	}
+	return center(square), true
+}
+
+func center(square v3.Sqaure) core.Coordinates {
+	return core.Coordinates{
+		Lat: round((square.SouthWest.Lat + square.NorthEast.Lat) / 2),
+		Lng: round((square.SouthWest.Lng + square.NorthEast.Lng) / 2),
+	}
+}
+
+// round rounds to the 6 decimals returned by the API.
+func round(f float64) float64 {
+	return math.Round(f*1e6) / 1e6
+}
+
+// distanceKm returns the great circle distance between a and b.
+func distanceKm(a, b core.Coordinates) float64 {
+	const earthRadiusKm = 6371
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat, dLng := lat2-lat1, (b.Lng-a.Lng)*math.Pi/180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
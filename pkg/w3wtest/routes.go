@@ -0,0 +1,525 @@
+package w3wtest
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+)
+
+const (
+	// maxGridDiagonalKm is the largest diagonal of a grid-section bounding box.
+	maxGridDiagonalKm = 4
+	// defaultNResults is the number of suggestions returned by default.
+	defaultNResults = 3
+	maxNResults     = 100
+)
+
+var regexCountry = regexp.MustCompile(`^[a-zA-Z]{2}$`)
+
+// badRequest is an input error answered with the 400 status code.
+type badRequest struct {
+	code    v3.ErrorCode
+	message string
+}
+
+type route func(q url.Values) (any, *badRequest)
+
+func (s *Server) newRoutes() map[string]route {
+	return map[string]route{
+		v3.EndpointConvertTo3wa:               s.convertTo3wa,
+		v3.EndpointConvertToCoordinates:       s.convertToCoordinates,
+		v3.EndpointAutoSuggest:                func(q url.Values) (any, *badRequest) { return s.autoSuggest(q, false) },
+		v3.EndpointAutoSuggestWithCoordinates: func(q url.Values) (any, *badRequest) { return s.autoSuggest(q, true) },
+		v3.EndpointAutoSuggestSelection:       s.autoSuggestSelection,
+		v3.EndpointGridSection:                s.gridSection,
+		v3.EndpointAvailableLanguages:         s.availableLanguages,
+	}
+}
+
+func (s *Server) serveRoute(w http.ResponseWriter, rt route, q url.Values) {
+	resp, bad := rt(q)
+	if bad != nil {
+		writeError(w, http.StatusBadRequest, bad.code, bad.message)
+		return
+	}
+	if resp == nil {
+		// Routes only reporting an event answer with an empty body.
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// square is the JSON format of a converted square.
+type square struct {
+	Country      string           `json:"country"`
+	Square       v3.Sqaure        `json:"square"`
+	NearestPlace string           `json:"nearestPlace"`
+	Coordinates  core.Coordinates `json:"coordinates"`
+	Words        string           `json:"words"`
+	Language     string           `json:"language"`
+	Locale       string           `json:"locale,omitempty"`
+	Map          string           `json:"map"`
+}
+
+type featureCollection struct {
+	Features []feature `json:"features"`
+	Type     string    `json:"type"`
+}
+
+type feature struct {
+	Bbox       []float64      `json:"bbox,omitempty"`
+	Geometry   geometry       `json:"geometry"`
+	Type       string         `json:"type"`
+	Properties map[string]any `json:"properties"`
+}
+
+type geometry struct {
+	Coordinates any    `json:"coordinates"`
+	Type        string `json:"type"`
+}
+
+func (s *Server) convertTo3wa(q url.Values) (any, *badRequest) {
+	if q.Get("coordinates") == "" {
+		return nil, &badRequest{v3.ErrorCodeMissingCoordinates, "coordinates must be specified"}
+	}
+	c, ok := parseCoordinates(q.Get("coordinates"))
+	if !ok {
+		return nil, &badRequest{v3.ErrorCodeBadCoordinates, "coordinates must be two comma separated lat,lng coordinates"}
+	}
+	sq, _ := Square(Words(c))
+	return s.convertResponse(q, Words(c), sq)
+}
+
+func (s *Server) convertToCoordinates(q url.Values) (any, *badRequest) {
+	words := strings.ToLower(strings.TrimPrefix(q.Get("words"), "///"))
+	if words == "" {
+		return nil, &badRequest{v3.ErrorCodeMissingWords, "words must be specified"}
+	}
+	sq, ok := Square(words)
+	if !ok {
+		return nil, &badRequest{v3.ErrorCodeBadWords, "Invalid or non-existent 3 word address"}
+	}
+	return s.convertResponse(q, words, sq)
+}
+
+func (s *Server) convertResponse(q url.Values, words string, sq v3.Sqaure) (any, *badRequest) {
+	language, locale, bad := s.language(q)
+	if bad != nil {
+		return nil, bad
+	}
+	resp := square{
+		Country:      s.country,
+		Square:       sq,
+		NearestPlace: s.place,
+		Coordinates:  center(sq),
+		Words:        words,
+		Language:     language,
+		Locale:       locale,
+		Map:          "https://w3w.co/" + words,
+	}
+	switch q.Get("format") {
+	case "", "json":
+		return resp, nil
+	case "geojson":
+		return featureCollection{
+			Type: "FeatureCollection",
+			Features: []feature{{
+				Bbox:     []float64{sq.SouthWest.Lng, sq.SouthWest.Lat, sq.NorthEast.Lng, sq.NorthEast.Lat},
+				Geometry: geometry{Type: "Point", Coordinates: []float64{resp.Coordinates.Lng, resp.Coordinates.Lat}},
+				Type:     "Feature",
+				Properties: map[string]any{
+					"country":      resp.Country,
+					"nearestPlace": resp.NearestPlace,
+					"words":        resp.Words,
+					"language":     resp.Language,
+					"map":          resp.Map,
+				},
+			}},
+		}, nil
+	}
+	return nil, badFormat
+}
+
+var badFormat = &badRequest{v3.ErrorCodeBadFormat, "format must be json or geojson"}
+
+// language returns the language and locale of the request, English by default.
+func (s *Server) language(q url.Values) (string, string, *badRequest) {
+	language, locale := q.Get("language"), q.Get("locale")
+	if language == "" && locale != "" {
+		language, _, _ = strings.Cut(locale, "_")
+	}
+	if language == "" {
+		return "en", "", nil
+	}
+	for _, l := range s.languages {
+		if l.Code == language {
+			if locale != "" && !strings.HasPrefix(locale, language+"_") {
+				return "", "", &badRequest{v3.ErrorCodeBadLocale, "locale must be a variant of the language"}
+			}
+			if locale != "" && len(l.Locales) > 0 && !slices.ContainsFunc(l.Locales, func(lo v3.Locale) bool { return lo.Code == locale }) {
+				return "", "", &badRequest{v3.ErrorCodeBadLocale, "locale must be a supported locale of the language"}
+			}
+			return language, locale, nil
+		}
+	}
+	return "", "", &badRequest{v3.ErrorCodeBadLanguage, "language must be a supported 3 word address language"}
+}
+
+// suggestion is the JSON format of a suggestion, holding the coordinates,
+// square and map only when returned by autosuggest-with-coordinates.
+type suggestion struct {
+	Country           string            `json:"country"`
+	NearestPlace      string            `json:"nearestPlace"`
+	Words             string            `json:"words"`
+	DistanceToFocusKm *int              `json:"distanceToFocusKm,omitempty"`
+	Rank              int               `json:"rank"`
+	Language          string            `json:"language"`
+	Locale            string            `json:"locale,omitempty"`
+	Coordinates       *core.Coordinates `json:"coordinates,omitempty"`
+	Square            *v3.Sqaure        `json:"square,omitempty"`
+	Map               string            `json:"map,omitempty"`
+}
+
+// autoSuggestOpts are the options of the autosuggest endpoints.
+type autoSuggestOpts struct {
+	focus         *core.Coordinates
+	countries     []string
+	boundingBox   *v3.BoundingBox
+	circle        *v3.Circle
+	polygon       []core.Coordinates
+	nResults      int
+	nFocusResults int
+	inputType     v3.InputType
+}
+
+// autoSuggest suggests addresses completing the last word of the input,
+// whose first two words must be complete, in the order of the fake words.
+// With a focus, the closest suggestions are returned first. Inputs of other
+// types than text are read with recognisedWords.
+func (s *Server) autoSuggest(q url.Values, withCoordinates bool) (any, *badRequest) {
+	input := q.Get("input")
+	if input == "" {
+		return nil, &badRequest{v3.ErrorCodeMissingInput, "input must be specified"}
+	}
+	opts, bad := parseAutoSuggestOpts(q)
+	if bad != nil {
+		return nil, bad
+	}
+	if opts.inputType != "" && opts.inputType != v3.InputTypeText {
+		input = recognisedWords(input, opts.inputType)
+	}
+	input = strings.ToLower(strings.TrimPrefix(input, "///"))
+	language, locale, bad := s.language(q)
+	if bad != nil {
+		return nil, bad
+	}
+
+	type candidate struct {
+		words  string
+		square v3.Sqaure
+		center core.Coordinates
+	}
+	var candidates []candidate
+	parts := strings.Split(input, ".")
+	if len(parts) == 3 && len(parts[2]) <= 5 {
+		_, ok1 := wordIndex(parts[0])
+		_, ok2 := wordIndex(parts[1])
+		for i := uint64(0); ok1 && ok2 && i < wordCount; i++ {
+			last := word(i)
+			if !strings.HasPrefix(last, parts[2]) {
+				continue
+			}
+			words := parts[0] + "." + parts[1] + "." + last
+			sq, ok := Square(words)
+			if !ok || !s.matches(opts, center(sq)) {
+				continue
+			}
+			candidates = append(candidates, candidate{words, sq, center(sq)})
+			if opts.focus == nil && len(candidates) == opts.nResults {
+				break
+			}
+		}
+	}
+	if opts.focus != nil {
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return distanceKm(*opts.focus, candidates[i].center) < distanceKm(*opts.focus, candidates[j].center)
+		})
+	}
+	if len(candidates) > opts.nResults {
+		candidates = candidates[:opts.nResults]
+	}
+
+	suggestions := make([]suggestion, 0, len(candidates))
+	for i, c := range candidates {
+		sg := suggestion{
+			Country:      s.country,
+			NearestPlace: s.place,
+			Words:        c.words,
+			Rank:         i + 1,
+			Language:     language,
+			Locale:       locale,
+		}
+		if opts.focus != nil && i < opts.nFocusResults {
+			distance := int(math.Round(distanceKm(*opts.focus, c.center)))
+			sg.DistanceToFocusKm = &distance
+		}
+		if withCoordinates {
+			center, square := c.center, c.square
+			sg.Coordinates, sg.Square = &center, &square
+			sg.Map = "https://w3w.co/" + c.words
+		}
+		suggestions = append(suggestions, sg)
+	}
+	return map[string][]suggestion{"suggestions": suggestions}, nil
+}
+
+// autoSuggestSelection accepts the selection of a fake address with its
+// rank and source API. Selections are only recorded, see Server.RequestsTo.
+func (s *Server) autoSuggestSelection(q url.Values) (any, *badRequest) {
+	if q.Get("raw-input") == "" {
+		return nil, &badRequest{v3.ErrorCodeMissingInput, "raw-input must be specified"}
+	}
+	selection := strings.ToLower(strings.TrimPrefix(q.Get("selection"), "///"))
+	if selection == "" {
+		return nil, &badRequest{v3.ErrorCodeMissingWords, "selection must be specified"}
+	}
+	if _, ok := Square(selection); !ok {
+		return nil, &badRequest{v3.ErrorCodeBadWords, "Invalid or non-existent 3 word address"}
+	}
+	if rank, err := strconv.Atoi(q.Get("rank")); err != nil || rank < 1 {
+		return nil, &badRequest{v3.ErrorCodeBadInput, "rank must be a positive integer"}
+	}
+	if source := q.Get("source-api"); source != v3.SourceAPIText && source != v3.SourceAPIVoice {
+		return nil, &badRequest{v3.ErrorCodeBadInput, "source-api must be text or voice"}
+	}
+	if _, bad := parseAutoSuggestOpts(q); bad != nil {
+		return nil, bad
+	}
+	if _, _, bad := s.language(q); bad != nil {
+		return nil, bad
+	}
+	return nil, nil
+}
+
+// recognisedWords returns the address recognised in the input of a voice or
+// OCR input type: the words of the first hypothesis of a VoCon Hybrid
+// payload, or the words of any other input separated by spaces or dots.
+// Payloads of other engines are not understood by the fake.
+func recognisedWords(input string, inputType v3.InputType) string {
+	if inputType == v3.InputTypeVoconHybrid {
+		var payload struct {
+			Hypotheses []struct {
+				Items []struct {
+					Orthography string `json:"_orthography"`
+				} `json:"_items"`
+			} `json:"_hypotheses"`
+		}
+		if json.Unmarshal([]byte(input), &payload) != nil || len(payload.Hypotheses) == 0 {
+			return ""
+		}
+		var words []string
+		for _, item := range payload.Hypotheses[0].Items {
+			words = append(words, item.Orthography)
+		}
+		input = strings.Join(words, " ")
+	}
+	return strings.Join(strings.FieldsFunc(input, func(r rune) bool { return r == ' ' || r == '.' }), ".")
+}
+
+// matches reports whether the coordinates satisfy the clipping options.
+func (s *Server) matches(opts autoSuggestOpts, c core.Coordinates) bool {
+	if len(opts.countries) > 0 {
+		found := false
+		for _, country := range opts.countries {
+			found = found || strings.EqualFold(country, s.country)
+		}
+		if !found {
+			return false
+		}
+	}
+	if bb := opts.boundingBox; bb != nil {
+		if c.Lat < bb.SouthWest.Lat || c.Lat > bb.NorthEast.Lat || c.Lng < bb.SouthWest.Lng || c.Lng > bb.NorthEast.Lng {
+			return false
+		}
+	}
+	if opts.circle != nil && distanceKm(opts.circle.Center, c) > opts.circle.RadiusKm {
+		return false
+	}
+	if len(opts.polygon) > 0 && !inPolygon(opts.polygon, c) {
+		return false
+	}
+	return true
+}
+
+func parseAutoSuggestOpts(q url.Values) (autoSuggestOpts, *badRequest) {
+	opts := autoSuggestOpts{nResults: defaultNResults}
+	if v := q.Get("focus"); v != "" {
+		focus, ok := parseCoordinates(v)
+		if !ok {
+			return opts, &badRequest{v3.ErrorCodeBadFocus, "focus must be two comma separated lat,lng coordinates"}
+		}
+		opts.focus = &focus
+	}
+	if v := q.Get("clip-to-country"); v != "" {
+		opts.countries = strings.Split(v, ",")
+		for _, country := range opts.countries {
+			if !regexCountry.MatchString(country) {
+				return opts, &badRequest{v3.ErrorCodeBadClipToCountry, "clip-to-country must be a comma separated list of 2 letter country codes"}
+			}
+		}
+	}
+	if v := q.Get("clip-to-bounding-box"); v != "" {
+		values, ok := parseFloats(v, 4)
+		if !ok || values[0] > values[2] || values[1] > values[3] {
+			return opts, &badRequest{v3.ErrorCodeBadClipToBoundingBox, "clip-to-bounding-box must be south,west,north,east coordinates"}
+		}
+		opts.boundingBox = &v3.BoundingBox{
+			SouthWest: core.Coordinates{Lat: values[0], Lng: values[1]},
+			NorthEast: core.Coordinates{Lat: values[2], Lng: values[3]},
+		}
+	}
+	if v := q.Get("clip-to-circle"); v != "" {
+		values, ok := parseFloats(v, 3)
+		if !ok || values[2] <= 0 {
+			return opts, &badRequest{v3.ErrorCodeBadClipToCircle, "clip-to-circle must be lat,lng,radius with a positive radius in kilometres"}
+		}
+		opts.circle = &v3.Circle{Center: core.Coordinates{Lat: values[0], Lng: values[1]}, RadiusKm: values[2]}
+	}
+	if v := q.Get("clip-to-polygon"); v != "" {
+		values, ok := parseFloats(v, -1)
+		n := len(values) / 2
+		if !ok || len(values)%2 != 0 || n < 4 || n > 25 || values[0] != values[len(values)-2] || values[1] != values[len(values)-1] {
+			return opts, &badRequest{v3.ErrorCodeBadClipToPolygon, "clip-to-polygon must be a closed polygon of 4 to 25 lat,lng coordinates"}
+		}
+		for i := 0; i < len(values); i += 2 {
+			opts.polygon = append(opts.polygon, core.Coordinates{Lat: values[i], Lng: values[i+1]})
+		}
+	}
+	if v := q.Get("input-type"); v != "" {
+		opts.inputType = v3.InputType(v)
+		if !slices.Contains(v3.InputTypes, opts.inputType) {
+			return opts, &badRequest{v3.ErrorCodeBadInputType, "input-type must be one of the supported input types"}
+		}
+		if opts.inputType.IsVoice() && q.Get("language") == "" {
+			return opts, &badRequest{v3.ErrorCodeBadLanguage, "language must be specified for voice input types"}
+		}
+	}
+	if v := q.Get("prefer-land"); v != "" && v != "true" && v != "false" {
+		return opts, &badRequest{v3.ErrorCodeBadPreferLand, "prefer-land must be true or false"}
+	}
+	if v := q.Get("n-results"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxNResults {
+			return opts, &badRequest{v3.ErrorCodeBadNResults, "n-results must be a positive integer up to 100"}
+		}
+		opts.nResults = n
+	}
+	opts.nFocusResults = opts.nResults
+	if v := q.Get("n-focus-results"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > opts.nResults {
+			return opts, &badRequest{v3.ErrorCodeBadNFocusResults, "n-focus-results must be a positive integer up to n-results"}
+		}
+		opts.nFocusResults = n
+	}
+	return opts, nil
+}
+
+func (s *Server) gridSection(q url.Values) (any, *badRequest) {
+	if q.Get("bounding-box") == "" {
+		return nil, &badRequest{v3.ErrorCodeMissingBoundingBox, "bounding-box must be specified"}
+	}
+	values, ok := parseFloats(q.Get("bounding-box"), 4)
+	if !ok || values[0] > values[2] || values[1] > values[3] || values[0] < -90 || values[2] > 90 {
+		return nil, &badRequest{v3.ErrorCodeBadBoundingBox, "bounding-box must be south,west,north,east coordinates"}
+	}
+	south, west, north, east := values[0], values[1], values[2], values[3]
+	if distanceKm(core.Coordinates{Lat: south, Lng: west}, core.Coordinates{Lat: north, Lng: east}) > maxGridDiagonalKm {
+		return nil, &badRequest{v3.ErrorCodeBadBoundingBoxTooBig, "The diagonal of bounding-box may not be > 4km"}
+	}
+
+	type line struct {
+		Start core.Coordinates `json:"start"`
+		End   core.Coordinates `json:"end"`
+	}
+	var lines []line
+	for lat := math.Ceil((south+90)/CellSize)*CellSize - 90; lat <= north; lat += CellSize {
+		lines = append(lines, line{core.Coordinates{Lat: round(lat), Lng: west}, core.Coordinates{Lat: round(lat), Lng: east}})
+	}
+	for lng := math.Ceil((west+180)/CellSize)*CellSize - 180; lng <= east; lng += CellSize {
+		lines = append(lines, line{core.Coordinates{Lat: south, Lng: round(lng)}, core.Coordinates{Lat: north, Lng: round(lng)}})
+	}
+
+	switch q.Get("format") {
+	case "", "json":
+		if lines == nil {
+			lines = []line{}
+		}
+		return map[string][]line{"lines": lines}, nil
+	case "geojson":
+		multiLine := make([][][]float64, 0, len(lines))
+		for _, l := range lines {
+			multiLine = append(multiLine, [][]float64{{l.Start.Lng, l.Start.Lat}, {l.End.Lng, l.End.Lat}})
+		}
+		return featureCollection{
+			Type: "FeatureCollection",
+			Features: []feature{{
+				Geometry:   geometry{Type: "MultiLineString", Coordinates: multiLine},
+				Type:       "Feature",
+				Properties: map[string]any{},
+			}},
+		}, nil
+	}
+	return nil, badFormat
+}
+
+func (s *Server) availableLanguages(url.Values) (any, *badRequest) {
+	return map[string][]v3.Language{"languages": s.languages}, nil
+}
+
+// parseFloats parses comma separated numbers, expecting n of them unless n is -1.
+func parseFloats(s string, n int) ([]float64, bool) {
+	parts := strings.Split(s, ",")
+	if n >= 0 && len(parts) != n {
+		return nil, false
+	}
+	values := make([]float64, len(parts))
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, false
+		}
+		values[i] = value
+	}
+	return values, true
+}
+
+func parseCoordinates(s string) (core.Coordinates, bool) {
+	values, ok := parseFloats(s, 2)
+	if !ok || values[0] < -90 || values[0] > 90 {
+		return core.Coordinates{}, false
+	}
+	return core.Coordinates{Lat: values[0], Lng: values[1]}, true
+}
+
+// inPolygon reports whether c is inside the polygon, using ray casting.
+func inPolygon(polygon []core.Coordinates, c core.Coordinates) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Lat > c.Lat) != (b.Lat > c.Lat) && c.Lng < (b.Lng-a.Lng)*(c.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,235 @@
+// Package w3wtest provides an in-process fake of the what3words API, so that
+// code using the wrapper can be tested without network access or an API key.
+//
+// The fake implements every v3 route called by the wrapper. Three word
+// addresses and squares are derived from a fake grid, see Words and Square,
+// so that conversions are deterministic and consistent with each other: the
+// address of coordinates converts back to the centre of their square. Fake
+// addresses are made of words such as "bacad" and do not match the addresses
+// of the real grid. Invalid requests are answered with the error payloads
+// documented by the API, and any endpoint can be overridden to simulate
+// failures. Every request received is recorded.
+//
+// Example usage:
+//
+//	srv := w3wtest.NewServer()
+//	defer srv.Close()
+//	svc := w3w.NewService("any-key", w3w.WithCustomBaseURL(srv.URL))
+//
+//	srv.FailNext(v3.EndpointConvertTo3wa, 1, w3wtest.Error(http.StatusInternalServerError, v3.ErrorCodeInternalServerError, "Server Error"))
+package w3wtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+)
+
+// Request is a request received by the Server.
+type Request struct {
+	// Endpoint is the name of the route, such as convert-to-3wa.
+	Endpoint string
+	Query    url.Values
+	Header   http.Header
+	Time     time.Time
+}
+
+// Server is a fake what3words API listening on a local address. Use its URL
+// as the base URL of the wrapper. It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	apiKey    string
+	languages []v3.Language
+	country   string
+	place     string
+	routes    map[string]route
+
+	mu        sync.Mutex
+	requests  []Request
+	overrides map[string]*override
+}
+
+// override answers requests to an endpoint in place of the fake.
+type override struct {
+	handler http.Handler
+	// remaining is the number of requests left to answer, -1 for all.
+	remaining int
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithAPIKey only accepts requests made with the given key, requests with
+// another key fail with InvalidKey. By default any key is accepted, and only
+// requests without a key fail, with MissingKey.
+func WithAPIKey(key string) Option {
+	return func(s *Server) {
+		s.apiKey = key
+	}
+}
+
+// WithLanguages sets the languages returned by available-languages and
+// accepted by the other endpoints, the locales of languages having some
+// being the only ones accepted. Defaults to English, German and French.
+// Addresses are the same in every language.
+func WithLanguages(languages ...v3.Language) Option {
+	return func(s *Server) {
+		s.languages = languages
+	}
+}
+
+// WithPlace sets the country and nearest place of every square. Defaults to
+// the ZZ country code and "Fakeville".
+func WithPlace(country, nearestPlace string) Option {
+	return func(s *Server) {
+		s.country, s.place = country, nearestPlace
+	}
+}
+
+// NewServer starts a fake what3words API, which must be closed once done.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		languages: []v3.Language{
+			{Code: "en", Name: "English", NativeName: "English"},
+			{Code: "de", Name: "German", NativeName: "Deutsch"},
+			{Code: "fr", Name: "French", NativeName: "Français"},
+		},
+		country:   "ZZ",
+		place:     "Fakeville",
+		overrides: make(map[string]*override),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.routes = s.newRoutes()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Override answers every request to the endpoint, such as
+// v3.EndpointConvertTo3wa, with the handler instead of the fake.
+func (s *Server) Override(endpoint string, handler http.Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.overrides[endpoint] = &override{handler: handler, remaining: -1}
+}
+
+// FailNext answers the next n requests to the endpoint with the handler, and
+// later requests with the fake, for example to test retries.
+func (s *Server) FailNext(endpoint string, n int, handler http.Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.overrides[endpoint] = &override{handler: handler, remaining: n}
+}
+
+// ClearOverrides removes the overrides of every endpoint.
+func (s *Server) ClearOverrides() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.overrides = make(map[string]*override)
+}
+
+// Requests returns the requests received so far, in the order they were received.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// RequestsTo returns the requests received so far by the endpoint.
+func (s *Server) RequestsTo(endpoint string) []Request {
+	var requests []Request
+	for _, r := range s.Requests() {
+		if r.Endpoint == endpoint {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
+
+// ResetRequests forgets the requests received so far.
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+// Error returns a handler answering with the error payload of the API.
+func Error(status int, code v3.ErrorCode, message string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeError(w, status, code, message)
+	})
+}
+
+// JSON returns a handler answering with the JSON encoding of body.
+func JSON(status int, body any) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, status, body)
+	})
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	endpoint := strings.TrimPrefix(r.URL.Path, "/v3/")
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Endpoint: endpoint,
+		Query:    r.URL.Query(),
+		Header:   r.Header.Clone(),
+		Time:     time.Now(),
+	})
+	var handler http.Handler
+	if o := s.overrides[endpoint]; o != nil {
+		handler = o.handler
+		if o.remaining > 0 {
+			o.remaining--
+		}
+		if o.remaining == 0 {
+			delete(s.overrides, endpoint)
+		}
+	}
+	s.mu.Unlock()
+	if handler != nil {
+		handler.ServeHTTP(w, r)
+		return
+	}
+
+	rt, ok := s.routes[endpoint]
+	if !ok || !strings.HasPrefix(r.URL.Path, "/v3/") {
+		writeError(w, http.StatusNotFound, "NotFound", "Unknown endpoint "+r.URL.Path)
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "Only GET requests are supported")
+		return
+	}
+	key := r.Header.Get("X-Api-Key")
+	if key == "" {
+		key = r.URL.Query().Get("key")
+	}
+	switch {
+	case key == "":
+		writeError(w, http.StatusUnauthorized, v3.ErrorCodeMissingKey, "Authentication failed; missing API key")
+		return
+	case s.apiKey != "" && key != s.apiKey:
+		writeError(w, http.StatusUnauthorized, v3.ErrorCodeInvalidKey, "Authentication failed; invalid API key")
+		return
+	}
+	s.serveRoute(w, rt, r.URL.Query())
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, code v3.ErrorCode, message string) {
+	writeJSON(w, status, map[string]v3.ErrorResponse{"error": {Code: code, Message: message}})
+}
@@ -0,0 +1,273 @@
+package w3wtest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/core"
+	"github.com/what3words/w3w-go-wrapper/pkg/w3wtest"
+)
+
+func setup(t *testing.T, opts ...w3wtest.Option) (*w3wtest.Server, v3.API) {
+	srv := w3wtest.NewServer(opts...)
+	t.Cleanup(srv.Close)
+	return srv, v3.NewAPI("test-key", v3.WithCustomBaseURL(srv.URL))
+}
+
+func TestGridRoundTrip(t *testing.T) {
+	points := []core.Coordinates{
+		{Lat: 51.520847, Lng: -0.195521},
+		{Lat: -33.856784, Lng: 151.215297},
+		{Lat: 0, Lng: 0},
+		{Lat: 89.999999, Lng: 179.999999},
+		{Lat: -90, Lng: -180},
+	}
+	for _, point := range points {
+		words := w3wtest.Words(point)
+		square, ok := w3wtest.Square(words)
+		if !ok {
+			t.Fatalf("ERROR: Expected %q of %v to be a fake address", words, point)
+		}
+		if point.Lat < square.SouthWest.Lat-1e-6 || point.Lat > square.NorthEast.Lat+1e-6 ||
+			point.Lng < square.SouthWest.Lng-1e-6 || point.Lng > square.NorthEast.Lng+1e-6 {
+			t.Fatalf("ERROR: Expected %v to be inside the square %+v of %q", point, square, words)
+		}
+		center, _ := w3wtest.Coordinates(words)
+		if w3wtest.Words(center) != words {
+			t.Fatalf("ERROR: Expected the centre of %q to have the same address, got %q", words, w3wtest.Words(center))
+		}
+	}
+	a := w3wtest.Words(core.Coordinates{Lat: 51.520847, Lng: -0.195521})
+	b := w3wtest.Words(core.Coordinates{Lat: 51.520847, Lng: -0.195521 + w3wtest.CellSize})
+	if a == b || strings.Split(a, ".")[0] == strings.Split(b, ".")[0] && strings.Split(a, ".")[1] == strings.Split(b, ".")[1] {
+		t.Fatalf("ERROR: Expected neighbouring cells to have unrelated addresses, got %q and %q", a, b)
+	}
+	if _, ok := w3wtest.Square("filled.count.soap"); ok {
+		t.Fatal("ERROR: Expected filled.count.soap not to be a fake address")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	_, api := setup(t)
+	ctx := context.Background()
+	point := core.Coordinates{Lat: 51.520847, Lng: -0.195521}
+
+	resp, err := api.ConvertTo3wa(ctx, point, &v3.ConvertAPIOpts{Language: "de"})
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if resp.Words != w3wtest.Words(point) || resp.Language != "de" || resp.Country != "ZZ" {
+		t.Fatalf("ERROR: Unexpected response %+v", resp)
+	}
+
+	coords, err := api.ConvertToCoordinates(ctx, resp.Words, nil)
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if coords.Square != resp.Square || coords.Coordinates != resp.Coordinates {
+		t.Fatalf("ERROR: Expected both conversions to return the same square, got %+v and %+v", resp, coords)
+	}
+
+	geo, err := api.ConvertToCoordinatesGeoJson(ctx, resp.Words, nil)
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if len(geo.Features) != 1 || geo.Features[0].Properties.Words != resp.Words ||
+		geo.Features[0].Geometry.Coordinates[1] != resp.Coordinates.Lat {
+		t.Fatalf("ERROR: Unexpected GeoJSON response %+v", geo)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	_, api := setup(t, w3wtest.WithAPIKey("right-key"))
+	ctx := context.Background()
+
+	_, err := api.AvailableLanguages(ctx)
+	if !errors.Is(err, v3.ErrInvalidKey) {
+		t.Fatalf("ERROR: Expected ErrInvalidKey, got %v", err)
+	}
+	api.SetHeader(core.HEADER_API_KEY, "right-key")
+	if _, err := api.ConvertToCoordinates(ctx, "filled.count.soap", nil); !errors.Is(err, v3.ErrBadWords) {
+		t.Fatalf("ERROR: Expected ErrBadWords, got %v", err)
+	}
+	if _, err := api.ConvertTo3wa(ctx, core.Coordinates{Lat: 51.5, Lng: -0.1}, &v3.ConvertAPIOpts{Language: "xx"}); !errors.Is(err, v3.ErrBadLanguage) {
+		t.Fatalf("ERROR: Expected ErrBadLanguage, got %v", err)
+	}
+	_, err = api.GridSection(ctx, v3.BoundingBox{
+		SouthWest: core.Coordinates{Lat: 51, Lng: 0},
+		NorthEast: core.Coordinates{Lat: 52, Lng: 1},
+	})
+	var httpErr *v3.HTTPError
+	if !errors.Is(err, v3.ErrBadBoundingBoxTooBig) || !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("ERROR: Expected ErrBadBoundingBoxTooBig with status 400, got %v", err)
+	}
+}
+
+func TestAutoSuggest(t *testing.T) {
+	_, api := setup(t)
+	ctx := context.Background()
+	words := w3wtest.Words(core.Coordinates{Lat: 51.520847, Lng: -0.195521})
+	partial := words[:len(words)-2]
+
+	resp, err := api.AutoSuggestWithCoordinates(ctx, partial, &v3.AutoSuggestOpts{NResults: core.Int(5)})
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if len(resp.Suggestions) == 0 || len(resp.Suggestions) > 5 {
+		t.Fatalf("ERROR: Expected between 1 and 5 suggestions, got %d", len(resp.Suggestions))
+	}
+	found := false
+	for i, s := range resp.Suggestions {
+		if !strings.HasPrefix(s.Words, partial) || s.Rank != i+1 {
+			t.Fatalf("ERROR: Unexpected suggestion %+v for %q", s, partial)
+		}
+		if square, _ := w3wtest.Square(s.Words); square != s.Square {
+			t.Fatalf("ERROR: Expected the square of %q to be %+v, got %+v", s.Words, square, s.Square)
+		}
+		found = found || s.Words == words
+	}
+	if !found {
+		t.Fatalf("ERROR: Expected %q to be suggested for %q", words, partial)
+	}
+
+	clipped, err := api.AutoSuggest(ctx, partial, &v3.AutoSuggestOpts{ClipToCountry: []string{"GB"}})
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	if len(clipped.Suggestions) != 0 {
+		t.Fatalf("ERROR: Expected no suggestion outside the ZZ country, got %+v", clipped.Suggestions)
+	}
+
+	voice := strings.ReplaceAll(words, ".", " ")
+	vocon := `{"_isInGrammar":"yes","_hypotheses":[{"_items":[{"_orthography":"` + strings.Join(strings.Split(words, "."), `"},{"_orthography":"`) + `"}]}]}`
+	for inputType, input := range map[v3.InputType]string{v3.InputTypeGenericVoice: voice, v3.InputTypeVoconHybrid: vocon} {
+		resp, err := api.AutoSuggest(ctx, input, &v3.AutoSuggestOpts{InputType: inputType, Language: "en"})
+		if err != nil {
+			t.Fatalf("ERROR: Got error %v", err)
+		}
+		if len(resp.Suggestions) == 0 || resp.Suggestions[0].Words != words {
+			t.Fatalf("ERROR: Expected %q to be suggested for the %s input %q, got %+v", words, inputType, input, resp.Suggestions)
+		}
+	}
+	_, err = api.Clone(v3.WithoutValidation()).AutoSuggest(ctx, voice, &v3.AutoSuggestOpts{InputType: v3.InputTypeGenericVoice})
+	if !errors.Is(err, v3.ErrBadLanguage) {
+		t.Fatalf("ERROR: Expected ErrBadLanguage for a voice input without language, got %v", err)
+	}
+}
+
+func TestAutoSuggestNFocusResults(t *testing.T) {
+	srv, api := setup(t)
+	ctx := context.Background()
+	words := w3wtest.Words(core.Coordinates{Lat: 51.520847, Lng: -0.195521})
+	partial := words[:len(words)-2]
+	focus := &core.Coordinates{Lat: 51.520847, Lng: -0.195521}
+
+	if _, err := api.AutoSuggest(ctx, partial, &v3.AutoSuggestOpts{Focus: focus, NResults: core.Int(3), NFocusResult: core.Int(1)}); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	requests := srv.RequestsTo(v3.EndpointAutoSuggest)
+	if query := requests[len(requests)-1].Query; query.Get("n-focus-results") != "1" || query.Has("n-focus-result") {
+		t.Fatalf("ERROR: Expected NFocusResult to be sent as n-focus-results, got %v", query)
+	}
+	_, err := api.Clone(v3.WithoutValidation()).AutoSuggest(ctx, partial, &v3.AutoSuggestOpts{Focus: focus, NResults: core.Int(1), NFocusResult: core.Int(3)})
+	if !errors.Is(err, v3.ErrBadNFocusResults) {
+		t.Fatalf("ERROR: Expected ErrBadNFocusResults for more focus results than results, got %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/v3/autosuggest?input="+partial+"&focus=51.520847,-0.195521&n-focus-result=0", nil)
+	req.Header.Set(core.HEADER_API_KEY, "test-key")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("ERROR: Expected the undocumented n-focus-result to be ignored, got status %d", resp.StatusCode)
+	}
+}
+
+func TestAutoSuggestSelection(t *testing.T) {
+	srv, api := setup(t)
+	ctx := context.Background()
+	words := w3wtest.Words(core.Coordinates{Lat: 51.520847, Lng: -0.195521})
+	opts := &v3.AutoSuggestOpts{Focus: &core.Coordinates{Lat: 51.52, Lng: -0.19}}
+
+	resp, err := api.AutoSuggest(ctx, words[:len(words)-2], opts)
+	if err != nil || len(resp.Suggestions) == 0 {
+		t.Fatalf("ERROR: Expected suggestions, got %+v, %v", resp, err)
+	}
+	selected := resp.Suggestions[len(resp.Suggestions)-1]
+	if err := api.AutoSuggestSelection(ctx, words[:len(words)-2], selected, opts); err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	requests := srv.RequestsTo(v3.EndpointAutoSuggestSelection)
+	if len(requests) != 1 {
+		t.Fatalf("ERROR: Expected 1 selection request, got %d", len(requests))
+	}
+	q := requests[0].Query
+	if q.Get("raw-input") != words[:len(words)-2] || q.Get("selection") != selected.Words ||
+		q.Get("rank") != strconv.Itoa(selected.Rank) || q.Get("source-api") != v3.SourceAPIText || q.Get("focus") == "" {
+		t.Fatalf("ERROR: Unexpected selection query %v", q)
+	}
+
+	err = api.AutoSuggestSelection(ctx, "filled.count.so", v3.AutoSuggestSuggestion{Words: "filled.count.soap", Rank: 1}, nil)
+	if !errors.Is(err, v3.ErrBadWords) {
+		t.Fatalf("ERROR: Expected ErrBadWords for an address outside the fake grid, got %v", err)
+	}
+}
+
+func TestGridSection(t *testing.T) {
+	_, api := setup(t)
+	resp, err := api.GridSection(context.Background(), v3.BoundingBox{
+		SouthWest: core.Coordinates{Lat: 52.207988, Lng: 0.116126},
+		NorthEast: core.Coordinates{Lat: 52.208167, Lng: 0.116426},
+	})
+	if err != nil {
+		t.Fatalf("ERROR: Got error %v", err)
+	}
+	// 7 horizontal and 11 vertical lines cross the bounding box.
+	if len(resp.Lines) != 18 {
+		t.Fatalf("ERROR: Expected 18 lines, got %d", len(resp.Lines))
+	}
+}
+
+func TestOverridesAndRecording(t *testing.T) {
+	srv := w3wtest.NewServer()
+	defer srv.Close()
+	policy := core.DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	api := v3.NewAPI("test-key", v3.WithCustomBaseURL(srv.URL), v3.WithRetryPolicy(policy))
+	ctx := context.Background()
+
+	srv.FailNext(v3.EndpointAvailableLanguages, 2, w3wtest.Error(http.StatusServiceUnavailable, v3.ErrorCodeInternalServerError, "Server Error"))
+	resp, err := api.AvailableLanguages(ctx)
+	if err != nil {
+		t.Fatalf("ERROR: Expected the third attempt to succeed, got %v", err)
+	}
+	if len(resp.Languages) != 3 {
+		t.Fatalf("ERROR: Expected 3 languages, got %d", len(resp.Languages))
+	}
+	requests := srv.RequestsTo(v3.EndpointAvailableLanguages)
+	if len(requests) != 3 || requests[0].Header.Get(core.HEADER_API_KEY) != "test-key" {
+		t.Fatalf("ERROR: Expected 3 recorded requests with the key, got %+v", requests)
+	}
+
+	srv.Override(v3.EndpointConvertToCoordinates, w3wtest.JSON(http.StatusOK, map[string]any{"words": "custom.fake.words"}))
+	coords, err := api.ConvertToCoordinates(ctx, "any.three.words", nil)
+	if err != nil || coords.Words != "custom.fake.words" {
+		t.Fatalf("ERROR: Expected the overridden response, got %+v, %v", coords, err)
+	}
+	srv.ClearOverrides()
+	if _, err := api.ConvertToCoordinates(ctx, "any.three.words", nil); !errors.Is(err, v3.ErrBadWords) {
+		t.Fatalf("ERROR: Expected ErrBadWords once overrides are cleared, got %v", err)
+	}
+	if query := srv.Requests()[len(srv.Requests())-1].Query; query.Get("words") != "any.three.words" {
+		t.Fatalf("ERROR: Expected the query to be recorded, got %v", query)
+	}
+}
@@ -27,6 +27,8 @@ var (
 //		return err
 //	}
 //
+// The w3wfake package provides an in-memory Service for tests.
+//
 //go:generate mockery --name Service --output ./mocks --outpkg mocks --case underscore
 type Service interface {
 
@@ -114,6 +116,40 @@ func WithClient(client client.HttpClient) ServiceOpts {
 	}
 }
 
+// WithRetryPolicy allows you to enable automatic retries of failed requests
+// for the What3Words service. Requests failing with a transport error or a
+// retryable status code (such as 429 or 503) are retried with exponential
+// backoff and jitter, honouring the `Retry-After` header when present.
+//
+// # Note:
+// The retry policy is applied to all API versions within the Service.
+//
+// Example usage:
+//
+//	service := NewService(apiKey, WithRetryPolicy(core.DefaultRetryPolicy()))
+func WithRetryPolicy(policy core.RetryPolicy) ServiceOpts {
+	return func(svc *service) {
+		svc.v3api.SetRetryPolicy(&policy)
+	}
+}
+
+// WithMiddleware allows you to wrap the HTTP client of the What3Words service
+// with middlewares, for example to inject authentication, log or trace requests.
+// The first middleware is the outermost and sees each request first. The
+// logical endpoint name can be retrieved using `v3.EndpointFromContext`.
+//
+// # Note:
+// The middlewares are applied to all API versions within the Service.
+//
+// Example usage:
+//
+//	service := NewService(apiKey, WithMiddleware(logging, tracing))
+func WithMiddleware(mw ...v3.Middleware) ServiceOpts {
+	return func(svc *service) {
+		svc.v3api.AddMiddleware(mw...)
+	}
+}
+
 // WithV3API allows you to set a custom What3Words v3 service.
 // You can construct a v3 service using the w3w-go-wrapper/pkg/v3 `NewService` function
 // and configure it as needed before setting it.
@@ -0,0 +1,56 @@
+//go:build live
+
+// The tests of this file check the service against the live API, they are
+// run with `go test -tags live ./...` and the X_API_KEY environment
+// variable. API_URL optionally sets the base URL of the API.
+
+package w3wgowrapper_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+
+	w3w "github.com/what3words/w3w-go-wrapper"
+)
+
+var (
+	apiURL = os.Getenv("API_URL")
+	apiKey = os.Getenv("X_API_KEY")
+)
+
+func setupLiveSvc(t *testing.T) w3w.Service {
+	if apiKey == "" {
+		t.Fatal("ERROR: X_API_KEY is empty or not found")
+	}
+	httpClient := CustomClient{*http.DefaultClient, t, apiURL}
+	svc := w3w.NewService(apiKey, w3w.WithClient(httpClient), w3w.WithCustomHeader("x-temp-header", "temp"), w3w.WithCustomBaseURL(apiURL))
+	return svc
+}
+
+func TestLiveAPI(t *testing.T) {
+	w3wAPI := setupLiveSvc(t)
+	_, err := w3wAPI.V3().AvailableLanguages(context.Background())
+	if err != nil {
+		t.Fatalf("ERROR: %+v", err)
+	}
+}
+
+func TestLiveIsValid3wa(t *testing.T) {
+	w3wAPI := setupLiveSvc(t)
+	valid := []string{"filled.count.soap"}
+	invalid := []string{"nuts.bolts.tires"}
+
+	for _, v := range valid {
+		if !w3wAPI.IsValid3wa(context.Background(), v) {
+			t.Fatalf("ERROR: %v is a valid what3words but it was not identified", v)
+		}
+	}
+
+	for _, v := range invalid {
+		if w3wAPI.IsValid3wa(context.Background(), v) {
+			t.Fatalf("ERROR: %v is an invalid what3words form but it was identified", v)
+		}
+	}
+}
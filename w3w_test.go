@@ -2,6 +2,7 @@ package w3wgowrapper_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -11,21 +12,20 @@ import (
 
 	w3w "github.com/what3words/w3w-go-wrapper"
 	v3 "github.com/what3words/w3w-go-wrapper/pkg/apis/v3"
+	"github.com/what3words/w3w-go-wrapper/pkg/w3wtest"
 )
 
-var (
-	apiURL = os.Getenv("API_URL")
-	apiKey = os.Getenv("X_API_KEY")
-)
-
+// CustomClient checks the requests are sent to the base URL with the
+// custom header of the service.
 type CustomClient struct {
 	http.Client
-	t *testing.T
+	t       *testing.T
+	baseURL string
 }
 
 func (m CustomClient) Do(req *http.Request) (*http.Response, error) {
 
-	if !strings.HasPrefix(req.URL.String(), apiURL) {
+	if !strings.HasPrefix(req.URL.String(), m.baseURL) {
 		m.t.Fatalf("ERROR: unexpected API URL: %s", req.URL.String())
 	}
 
@@ -38,12 +38,13 @@ func (m CustomClient) Do(req *http.Request) (*http.Response, error) {
 	return resp, err
 }
 
+// setupSvc returns a service using the fake API of w3wtest, see
+// w3w_live_test.go for the tests against the live API.
 func setupSvc(t *testing.T) w3w.Service {
-	if apiKey == "" {
-		t.Fatal("ERROR: X_API_KEY is empty or not found")
-	}
-	httpClient := CustomClient{*http.DefaultClient, t}
-	svc := w3w.NewService(apiKey, w3w.WithClient(httpClient), w3w.WithCustomHeader("x-temp-header", "temp"), w3w.WithCustomBaseURL(apiURL))
+	srv := w3wtest.NewServer()
+	t.Cleanup(srv.Close)
+	httpClient := CustomClient{*http.DefaultClient, t, srv.URL}
+	svc := w3w.NewService("test-key", w3w.WithClient(httpClient), w3w.WithCustomHeader("x-temp-header", "temp"), w3w.WithCustomBaseURL(srv.URL))
 	return svc
 }
 
@@ -56,7 +57,8 @@ func TestAPI(t *testing.T) {
 }
 
 func TestFindPossible3wa(t *testing.T) {
-	w3wAPI := setupSvc(t)
+	// Only the regular expressions are used, no request is made.
+	w3wAPI := w3w.NewService("")
 	source := "Can be found at filled.count.soap and at ///test.fake.words but not at test.fake. or test.fake"
 	pa := w3wAPI.FindPossible3wa(source)
 	expected := []string{"filled.count.soap", "test.fake.words"}
@@ -69,7 +71,8 @@ func TestFindPossible3wa(t *testing.T) {
 }
 
 func TestDidYouMean(t *testing.T) {
-	w3wAPI := setupSvc(t)
+	// Only the regular expressions are used, no request is made.
+	w3wAPI := w3w.NewService("")
 	valid := []string{"filled.count.soap", "filled-count-soap"}
 	invalid := []string{"filled.count", "filled.count."}
 
@@ -87,7 +90,8 @@ func TestDidYouMean(t *testing.T) {
 }
 
 func TestIsPossible3wa(t *testing.T) {
-	w3wAPI := setupSvc(t)
+	// Only the regular expressions are used, no request is made.
+	w3wAPI := w3w.NewService("")
 	valid := []string{"filled.count.soap"}
 	invalid := []string{"filled.count", "filled.count.", "filled-count-soap"}
 
@@ -106,8 +110,9 @@ func TestIsPossible3wa(t *testing.T) {
 
 func TestIsValid3wa(t *testing.T) {
 	w3wAPI := setupSvc(t)
-	valid := []string{"filled.count.soap"}
-	invalid := []string{"nuts.bolts.tires"}
+	valid := []string{w3wtest.Words(v3.Coordinates{Lat: 51.520847, Lng: -0.195521})}
+	// Real addresses are not addresses of the fake grid.
+	invalid := []string{"filled.count.soap", "nuts.bolts.tires"}
 
 	for _, v := range valid {
 		if !w3wAPI.IsValid3wa(context.Background(), v) {
@@ -134,7 +139,7 @@ func ExampleService() {
 	}
 }
 
-func ExampleConvertToCoordinates() {
+func ExampleService_convertToCoordinates() {
 	apiKey := os.Getenv("X_API_KEY")
 	if apiKey == "" {
 		panic("ERROR: X_API_KEY is empty or not found")
@@ -148,7 +153,7 @@ func ExampleConvertToCoordinates() {
 	fmt.Println(resp.Coordinates)
 }
 
-func ExampleConvertToCoordinatesLanguage() {
+func ExampleService_convertToCoordinatesLanguage() {
 	apiKey := os.Getenv("X_API_KEY")
 	if apiKey == "" {
 		panic("ERROR: X_API_KEY is empty or not found")
@@ -163,7 +168,7 @@ func ExampleConvertToCoordinatesLanguage() {
 	fmt.Println(resp.Coordinates)
 }
 
-func ExampleConvertToCoordinatesGeoJson() {
+func ExampleService_convertToCoordinatesGeoJson() {
 	apiKey := os.Getenv("X_API_KEY")
 	if apiKey == "" {
 		panic("ERROR: X_API_KEY is empty or not found")
@@ -177,7 +182,7 @@ func ExampleConvertToCoordinatesGeoJson() {
 	fmt.Println(resp.Features[0].Geometry.Coordinates)
 }
 
-func ExampleConvertTo3wa() {
+func ExampleService_convertTo3wa() {
 	apiKey := os.Getenv("X_API_KEY")
 	if apiKey == "" {
 		panic("ERROR: X_API_KEY is empty or not found")
@@ -193,7 +198,7 @@ func ExampleConvertTo3wa() {
 	fmt.Println(resp.Words)
 }
 
-func ExampleGridSection() {
+func ExampleService_gridSection() {
 	apiKey := os.Getenv("X_API_KEY")
 	if apiKey == "" {
 		panic("ERROR: X_API_KEY is empty or not found")
@@ -215,7 +220,7 @@ func ExampleGridSection() {
 	fmt.Println(resp.Lines[0])
 }
 
-func ExampleAutoSuggest() {
+func ExampleService_autoSuggest() {
 	apiKey := os.Getenv("X_API_KEY")
 	if apiKey == "" {
 		panic("ERROR: X_API_KEY is empty or not found")
@@ -236,7 +241,7 @@ func ExampleAutoSuggest() {
 	fmt.Println(resp.Suggestions[0].Words)
 }
 
-func ExampleExactWhat3WordsAPIErrors() {
+func ExampleService_errors() {
 	apiKey := os.Getenv("X_API_KEY")
 	if apiKey == "" {
 		panic("ERROR: X_API_KEY is empty or not found")
@@ -244,9 +249,10 @@ func ExampleExactWhat3WordsAPIErrors() {
 	svc := w3w.NewService(apiKey)
 	_, err := svc.V3().ConvertToCoordinates(context.Background(), "filled", nil)
 	if err != nil {
-		if err, ok := err.(*v3.ErrorResponse); ok {
+		var errResp *v3.ErrorResponse
+		if errors.As(err, &errResp) {
 			// Refer v3.ErrorCode for more types of errors
-			fmt.Println(err.Code)
+			fmt.Println(errResp.Code)
 		}
 	}
 }